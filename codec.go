@@ -0,0 +1,102 @@
+package ulid
+
+import "errors"
+
+// ErrInvalidAlphabet is returned by NewCodec when the alphabet is not 32 distinct characters.
+var ErrInvalidAlphabet = errors.New("ulid: invalid alphabet")
+
+// ErrUninitializedCodec is returned when a Codec that is not created by NewCodec is used.
+var ErrUninitializedCodec = errors.New("ulid: uninitialized codec")
+
+// A Codec encodes and decodes ULIDs with a custom base32 alphabet.
+// The package-level functions and methods of [ULID] always use the Crockford's Base32 alphabet.
+type Codec struct {
+	// toCustom maps the characters of the Crockford's Base32 alphabet to the custom alphabet.
+	toCustom [256]byte
+
+	// toCrockford maps the characters of the custom alphabet to the Crockford's Base32 alphabet.
+	// Characters not in the custom alphabet are mapped to 0, which is not a valid character.
+	toCrockford [256]byte
+}
+
+// NewCodec returns a new Codec that uses alphabet instead of the Crockford's Base32 alphabet.
+// The alphabet must consist of 32 distinct bytes, and it is case-sensitive.
+// The i-th byte of alphabet encodes the 5-bit value i.
+func NewCodec(alphabet string) (*Codec, error) {
+	if len(alphabet) != 32 {
+		return nil, ErrInvalidAlphabet
+	}
+	c := &Codec{}
+	for i := range len(alphabet) {
+		ch := alphabet[i]
+		if ch == 0 || c.toCrockford[ch] != 0 {
+			return nil, ErrInvalidAlphabet
+		}
+		c.toCustom[enc[i]] = ch
+		c.toCrockford[ch] = enc[i]
+	}
+	return c, nil
+}
+
+// initialized reports whether c is created by NewCodec.
+func (c *Codec) initialized() bool {
+	// Every character of the Crockford's Base32 alphabet is mapped to a non-zero byte by NewCodec.
+	return c != nil && c.toCustom['0'] != 0
+}
+
+func (c *Codec) text(id ULID) ([EncodedSize]byte, error) {
+	if !c.initialized() {
+		return [EncodedSize]byte{}, ErrUninitializedCodec
+	}
+	buf := id.text()
+	for i, ch := range buf {
+		buf[i] = c.toCustom[ch]
+	}
+	return buf, nil
+}
+
+// Encode returns the text encoding of id using the alphabet of c.
+// It panics if c is not created by [NewCodec]; use [Codec.MarshalText] to get an error instead.
+func (c *Codec) Encode(id ULID) string {
+	buf, err := c.text(id)
+	if err != nil {
+		panic(err)
+	}
+	return string(buf[:])
+}
+
+// MarshalText returns the text encoding of id using the alphabet of c.
+// It returns [ErrUninitializedCodec] if c is not created by [NewCodec].
+func (c *Codec) MarshalText(id ULID) ([]byte, error) {
+	buf, err := c.text(id)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:], nil
+}
+
+// AppendText appends the text encoding of id using the alphabet of c to b and returns the extended buffer.
+// It returns [ErrUninitializedCodec] if c is not created by [NewCodec].
+func (c *Codec) AppendText(b []byte, id ULID) ([]byte, error) {
+	buf, err := c.text(id)
+	if err != nil {
+		return b, err
+	}
+	return append(b, buf[:]...), nil
+}
+
+// Decode parses a ULID encoded using the alphabet of c.
+// It returns [ErrUninitializedCodec] if c is not created by [NewCodec].
+func (c *Codec) Decode(s string) (ULID, error) {
+	if !c.initialized() {
+		return ULID{}, ErrUninitializedCodec
+	}
+	if len(s) != EncodedSize {
+		return ULID{}, ErrInvalidSize
+	}
+	var buf [EncodedSize]byte
+	for i := range buf {
+		buf[i] = c.toCrockford[s[i]]
+	}
+	return parse(buf[:])
+}
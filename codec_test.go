@@ -0,0 +1,125 @@
+package ulid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCodec(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+	}{
+		{"too short", "0123456789ABCDEFGHJKMNPQRSTVWXY"},
+		{"too long", "0123456789ABCDEFGHJKMNPQRSTVWXYZ!"},
+		{"duplicated", "0123456789ABCDEFGHJKMNPQRSTVWXYY"},
+		{"nul", "0123456789ABCDEFGHJKMNPQRSTVWXY\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCodec(tt.alphabet)
+			if !errors.Is(err, ErrInvalidAlphabet) {
+				t.Fatalf("err=%v", err)
+			}
+		})
+	}
+}
+
+func TestCodec(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("crockford", func(t *testing.T) {
+		c, err := NewCodec(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := c.Encode(id)
+		if s != id.String() {
+			t.Fatalf("s=%s", s)
+		}
+	})
+
+	t.Run("permuted", func(t *testing.T) {
+		// the reversed Crockford's Base32 alphabet
+		c, err := NewCodec("ZYXWVTSRQPNMKJHGFEDCBA9876543210")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := c.Encode(id)
+		if s != "ZYN70WAJHC564V77GG8SPFTGN4" {
+			t.Fatalf("s=%s", s)
+		}
+		got, err := c.Decode(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+
+		// the Crockford's Base32 encoding is not valid in the permuted alphabet.
+		if _, err := c.Decode(id.String()); err == nil {
+			t.Fatal("want error, got nil")
+		}
+	})
+
+	t.Run("invalid character", func(t *testing.T) {
+		c, err := NewCodec("ZYXWVTSRQPNMKJHGFEDCBA9876543210")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.Decode("ZYN70WAJHC564V77GG8SPFTGN!")
+		if !errors.Is(err, ErrInvalidCharacter) {
+			t.Fatalf("err=%v", err)
+		}
+		_, err = c.Decode("ZYN70WAJHC564V77GG8SPFTGN")
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestCodec_MarshalText(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("valid", func(t *testing.T) {
+		c, err := NewCodec("ZYXWVTSRQPNMKJHGFEDCBA9876543210")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := c.MarshalText(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "ZYN70WAJHC564V77GG8SPFTGN4" {
+			t.Fatalf("data=%s", data)
+		}
+		data, err = c.AppendText([]byte("id="), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "id=ZYN70WAJHC564V77GG8SPFTGN4" {
+			t.Fatalf("data=%s", data)
+		}
+	})
+
+	t.Run("uninitialized", func(t *testing.T) {
+		var c Codec
+		if _, err := c.MarshalText(id); !errors.Is(err, ErrUninitializedCodec) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, err := c.AppendText(nil, id); !errors.Is(err, ErrUninitializedCodec) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, err := c.Decode("ZYN70WAJHC564V77GG8SPFTGN4"); !errors.Is(err, ErrUninitializedCodec) {
+			t.Fatalf("err=%v", err)
+		}
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		c.Encode(id)
+	})
+}
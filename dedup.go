@@ -0,0 +1,111 @@
+package ulid
+
+import (
+	"container/heap"
+	"time"
+)
+
+// A Deduper detects duplicated ULIDs in a stream, in any order.
+// It remembers every ULID it has seen, so its memory grows without bound;
+// use [WindowedDeduper] for long-running streams.
+// The zero value is an empty Deduper ready to use.
+// A Deduper is not safe for concurrent use by multiple goroutines.
+type Deduper struct {
+	seen map[ULID]struct{}
+}
+
+// Seen reports whether id has been seen before, and records it.
+func (d *Deduper) Seen(id ULID) bool {
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	if d.seen == nil {
+		d.seen = make(map[ULID]struct{})
+	}
+	d.seen[id] = struct{}{}
+	return false
+}
+
+// Len returns the number of ULIDs recorded in d.
+func (d *Deduper) Len() int {
+	return len(d.seen)
+}
+
+// A WindowedDeduper detects duplicated ULIDs in a stream, in any order,
+// remembering only the ULIDs whose time components are within a window before the current time.
+// Older ULIDs are forgotten, which bounds the memory for long-running streams.
+// A WindowedDeduper is not safe for concurrent use by multiple goroutines.
+type WindowedDeduper struct {
+	window time.Duration
+	clock  Clock
+	seen   map[ULID]struct{}
+	queue  ulidHeap // the ULIDs in seen, ordered by the time components
+}
+
+// NewWindowedDeduper returns a WindowedDeduper that remembers ULIDs for window,
+// reading the current time from clock.
+// If clock is nil, the wall clock is used.
+func NewWindowedDeduper(window time.Duration, clock Clock) *WindowedDeduper {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &WindowedDeduper{
+		window: window,
+		clock:  clock,
+		seen:   make(map[ULID]struct{}),
+	}
+}
+
+// Seen reports whether id has been seen within the window, and records it.
+// A ULID whose time component is already older than the window is neither recorded nor reported as seen,
+// because its duplicates may have been forgotten.
+// Likewise, a ULID whose time component is more than the window ahead of the current time
+// is neither recorded nor reported as seen; otherwise it would stay in memory until the clock catches up.
+func (d *WindowedDeduper) Seen(id ULID) bool {
+	now := d.clock.Now().UnixMilli()
+	window := d.window.Milliseconds()
+	cutoff := now - window
+	d.evict(cutoff)
+	if t := id.Time(); t < cutoff || t-now > window {
+		return false
+	}
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	d.seen[id] = struct{}{}
+	heap.Push(&d.queue, id)
+	return false
+}
+
+// Len returns the number of ULIDs recorded in d, including ones that have expired
+// but not yet been evicted by a call of Seen.
+func (d *WindowedDeduper) Len() int {
+	return len(d.seen)
+}
+
+// evict forgets the ULIDs whose time components are less than cutoff.
+func (d *WindowedDeduper) evict(cutoff int64) {
+	for len(d.queue) > 0 && d.queue[0].Time() < cutoff {
+		id := heap.Pop(&d.queue).(ULID)
+		delete(d.seen, id)
+	}
+}
+
+// ulidHeap is a min-heap of ULIDs implementing [heap.Interface].
+type ulidHeap []ULID
+
+func (h ulidHeap) Len() int           { return len(h) }
+func (h ulidHeap) Less(i, j int) bool { return h[i].Compare(h[j]) < 0 }
+func (h ulidHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *ulidHeap) Push(x any) {
+	*h = append(*h, x.(ULID))
+}
+
+func (h *ulidHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
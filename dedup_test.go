@@ -0,0 +1,107 @@
+package ulid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeduper(t *testing.T) {
+	ids := []ULID{
+		MinForTime(3),
+		MinForTime(1),
+		MaxForTime(2),
+		MinForTime(2),
+	}
+
+	var d Deduper
+	for _, id := range ids {
+		if d.Seen(id) {
+			t.Fatalf("%v is reported as seen", id)
+		}
+	}
+	for _, id := range ids {
+		if !d.Seen(id) {
+			t.Fatalf("%v is not reported as seen", id)
+		}
+	}
+	if d.Len() != 4 {
+		t.Fatalf("len=%d", d.Len())
+	}
+}
+
+func TestWindowedDeduper(t *testing.T) {
+	const now = 1469918176385
+	c := NewManualClock(time.UnixMilli(now))
+	d := NewWindowedDeduper(time.Second, c)
+
+	// out of order within the window
+	ids := []ULID{MinForTime(now), MinForTime(now - 500), MinForTime(now - 1000), MinForTime(now - 200)}
+	for _, id := range ids {
+		if d.Seen(id) {
+			t.Fatalf("%v is reported as seen", id)
+		}
+	}
+	for _, id := range ids {
+		if !d.Seen(id) {
+			t.Fatalf("%v is not reported as seen", id)
+		}
+	}
+	if d.Len() != 4 {
+		t.Fatalf("len=%d", d.Len())
+	}
+
+	// older than the window
+	old := MinForTime(now - 1001)
+	if d.Seen(old) || d.Seen(old) {
+		t.Fatalf("%v is reported as seen", old)
+	}
+	if d.Len() != 4 {
+		t.Fatalf("len=%d", d.Len())
+	}
+
+	// the ULIDs before now-400 expire.
+	c.Add(600 * time.Millisecond)
+	if !d.Seen(MinForTime(now - 200)) {
+		t.Fatal("unexpired ULID is not reported as seen")
+	}
+	if d.Len() != 2 {
+		t.Fatalf("len=%d", d.Len())
+	}
+	if d.Seen(MinForTime(now - 500)) {
+		t.Fatal("expired ULID is reported as seen")
+	}
+
+	// all expire.
+	c.Add(time.Hour)
+	if d.Seen(MinForTime(now + 3600600)) {
+		t.Fatal("new ULID is reported as seen")
+	}
+	if d.Len() != 1 {
+		t.Fatalf("len=%d", d.Len())
+	}
+}
+
+func TestWindowedDeduper_Future(t *testing.T) {
+	const now = 1469918176385
+	c := NewManualClock(time.UnixMilli(now))
+	d := NewWindowedDeduper(time.Second, c)
+
+	// within the window ahead of now
+	near := MinForTime(now + 1000)
+	if d.Seen(near) {
+		t.Fatalf("%v is reported as seen", near)
+	}
+	if !d.Seen(near) {
+		t.Fatalf("%v is not reported as seen", near)
+	}
+
+	// too far in the future
+	for _, id := range []ULID{MinForTime(now + 1001), MaxForTime(1<<48 - 1)} {
+		if d.Seen(id) || d.Seen(id) {
+			t.Fatalf("%v is reported as seen", id)
+		}
+	}
+	if d.Len() != 1 {
+		t.Fatalf("len=%d", d.Len())
+	}
+}
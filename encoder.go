@@ -0,0 +1,53 @@
+package ulid
+
+import (
+	"bufio"
+	"io"
+)
+
+// An Encoder encodes ULIDs into a reusable buffer, avoiding an allocation per ULID.
+// The zero value is ready to use.
+// An Encoder is not safe for concurrent use by multiple goroutines.
+type Encoder struct {
+	buf [EncodedSize]byte
+}
+
+// Encode returns the canonical text encoding of id.
+// The returned slice is backed by the buffer of e and is valid only until the next call to Encode,
+// so callers must write or copy it out immediately.
+func (e *Encoder) Encode(id ULID) []byte {
+	e.buf = id.text()
+	return e.buf[:]
+}
+
+// A Writer writes ULIDs in the canonical text encoding to an underlying [io.Writer],
+// each followed by a delimiter.
+// Writes are buffered; call Flush after the last ULID has been written.
+// Like [bufio.Writer], once an error occurs, all subsequent writes and flushes return the error.
+type Writer struct {
+	w     *bufio.Writer
+	delim byte
+	buf   [EncodedSize + 1]byte
+}
+
+// NewWriter returns a new Writer that writes to w, terminating each ULID with delim.
+func NewWriter(w io.Writer, delim byte) *Writer {
+	return &Writer{
+		w:     bufio.NewWriter(w),
+		delim: delim,
+	}
+}
+
+// Write writes the canonical text encoding of id followed by the delimiter.
+func (w *Writer) Write(id ULID) error {
+	text := id.text()
+	copy(w.buf[:], text[:])
+	w.buf[EncodedSize] = w.delim
+	_, err := w.w.Write(w.buf[:])
+	return err
+}
+
+// Flush writes any buffered data to the underlying [io.Writer].
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,94 @@
+package ulid
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var e Encoder
+	id1 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if s := string(e.Encode(id1)); s != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatalf("s=%s", s)
+	}
+	if s := string(e.Encode(Zero)); s != "00000000000000000000000000" {
+		t.Fatalf("s=%s", s)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { e.Encode(id1) }); allocs != 0 {
+		t.Fatalf("allocs=%v", allocs)
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	var e Encoder
+	id := Make()
+	b.ReportAllocs()
+	for b.Loop() {
+		runtime.KeepAlive(e.Encode(id))
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, '\n')
+	ids := make([]ULID, 10000)
+	for i := range ids {
+		ids[i] = Make()
+		if err := w.Write(ids[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != len(ids)*(EncodedSize+1) {
+		t.Fatalf("len=%d", buf.Len())
+	}
+
+	// read them back
+	s := bufio.NewScanner(&buf)
+	var i int
+	for s.Scan() {
+		id, err := Parse(s.Text())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != ids[i] {
+			t.Fatalf("want %v, got %v", ids[i], id)
+		}
+		i++
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(ids) {
+		t.Fatalf("read %d ULIDs, want %d", i, len(ids))
+	}
+}
+
+type errWriter struct{}
+
+var errWrite = errors.New("write error")
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestWriter_Error(t *testing.T) {
+	w := NewWriter(errWriter{}, '\n')
+	if err := w.Write(Zero); err != nil {
+		// the ULID is buffered and the error is not reported yet.
+		t.Fatal(err)
+	}
+	if err := w.Flush(); !errors.Is(err, errWrite) {
+		t.Fatalf("err=%v", err)
+	}
+
+	// the error is sticky.
+	if err := w.Write(Zero); !errors.Is(err, errWrite) {
+		t.Fatalf("err=%v", err)
+	}
+}
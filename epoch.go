@@ -0,0 +1,42 @@
+package ulid
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Epoch is a ULID that can also be unmarshaled from a bare JSON number of Unix milliseconds.
+// It bridges formats that carry only a timestamp:
+// such a number is converted to a ULID with that time component and a zero random component.
+// It is always marshaled to JSON as the canonical text encoding.
+type Epoch struct {
+	ULID
+}
+
+// MarshalJSON implements the [encoding/json.Marshaler] interface.
+func (e Epoch) MarshalJSON() ([]byte, error) {
+	return e.ULID.AppendJSON(nil), nil
+}
+
+// UnmarshalJSON implements the [encoding/json.Unmarshaler] interface.
+// It accepts either a JSON string of the text encoding or a JSON number of Unix milliseconds.
+// The JSON null value is a no-op.
+func (e *Epoch) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		return e.ULID.UnmarshalText(data[1 : len(data)-1])
+	}
+
+	ms, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("ulid: invalid JSON value: %s", data)
+	}
+	if ms < 0 || ms > 0xFFFFFFFFFFFF {
+		return fmt.Errorf("ulid: time must be between 0 and 2^48-1: %d", ms)
+	}
+	e.ULID = MinForTime(ms)
+	return nil
+}
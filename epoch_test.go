@@ -0,0 +1,79 @@
+package ulid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEpoch_MarshalJSON(t *testing.T) {
+	e := Epoch{ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}}
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"01ARZ3NDEKTSV4RRFFQ69G5FAV"` {
+		t.Fatalf("data=%s", data)
+	}
+}
+
+func TestEpoch_UnmarshalJSON(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		var e Epoch
+		if err := json.Unmarshal([]byte(`"01ARZ3NDEKTSV4RRFFQ69G5FAV"`), &e); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if e.ULID != want {
+			t.Fatalf("want %v, got %v", want, e.ULID)
+		}
+	})
+
+	t.Run("number", func(t *testing.T) {
+		var e Epoch
+		if err := json.Unmarshal([]byte(`1469922850259`), &e); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+		if e.ULID != want {
+			t.Fatalf("want %v, got %v", want, e.ULID)
+		}
+	})
+
+	t.Run("in a struct", func(t *testing.T) {
+		var v struct {
+			ID Epoch `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(`{"id": 1469922850259}`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.ID.Time() != 1469922850259 {
+			t.Fatalf("time=%d", v.ID.Time())
+		}
+	})
+
+	t.Run("max", func(t *testing.T) {
+		var e Epoch
+		if err := json.Unmarshal([]byte(`281474976710655`), &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Time() != 0xFFFFFFFFFFFF {
+			t.Fatalf("time=%d", e.Time())
+		}
+	})
+
+	invalid := []string{
+		`281474976710656`, // 2^48
+		`-1`,
+		`1.5`,
+		`"01ARZ3NDEKTSV4RRFFQ69G5FA"`,
+		`true`,
+	}
+	for _, data := range invalid {
+		t.Run(data, func(t *testing.T) {
+			var e Epoch
+			if err := json.Unmarshal([]byte(data), &e); err == nil {
+				t.Fatal("want error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package ulid
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	mathrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrMonotonicOverflow is returned by [Generator.MakeMonotonic]
+// when the random component overflows within the same millisecond.
+var ErrMonotonicOverflow = errors.New("ulid: monotonic overflow")
+
+// A Clock provides the current time to a [Generator].
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// A ManualClock is a [Clock] whose time only moves when Set or Add is called.
+// It is useful for testing.
+// It is safe for concurrent use by multiple goroutines.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a ManualClock set to t.
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now implements the [Clock] interface.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the time of the clock to t.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Add advances the time of the clock by d.
+func (c *ManualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// tickerClock is a [Clock] that advances by interval each time Now is called.
+type tickerClock struct {
+	mu       sync.Mutex
+	now      time.Time
+	interval time.Duration
+}
+
+func (c *tickerClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.interval)
+	return now
+}
+
+// A Generator generates ULIDs.
+// It is safe for concurrent use by multiple goroutines.
+type Generator struct {
+	clock   Clock
+	node    [2]byte
+	hasNode bool
+	floor   bool // whether Make clamps the time component to made
+
+	mu      sync.Mutex
+	entropy io.Reader // the source of the random component; nil means crypto/rand
+	buf     [10]byte  // scratch buffer for reading from entropy
+	last    ULID      // the last ULID generated by MakeMonotonic
+	count   uint64    // the number of ULIDs generated by MakeMonotonic in the millisecond of last
+	store   Store     // persists last; nil means no persistence
+	loaded  bool      // whether last has been loaded from store
+	made    ULID      // the last ULID generated by Make if floor is set
+}
+
+// A GeneratorOption configures a [Generator].
+type GeneratorOption func(*Generator)
+
+// WithClock returns a GeneratorOption that makes the Generator read the current time from c.
+func WithClock(c Clock) GeneratorOption {
+	return func(g *Generator) {
+		g.clock = c
+	}
+}
+
+// WithTimeFloor returns a GeneratorOption that makes [Generator.Make] never go back in time.
+// If the clock has not advanced past the time component of the last ULID generated by Make,
+// for example because the clock was stepped backward by NTP, the random component of the last ULID is incremented instead,
+// so the ULIDs are strictly increasing.
+// If the random component overflows, the time component is moved one millisecond ahead of the last ULID
+// and a new random component is used, so the ULIDs stay strictly increasing.
+// Make panics if the time component of the last ULID is already the maximum, 2^48-1.
+func WithTimeFloor() GeneratorOption {
+	return func(g *Generator) {
+		g.floor = true
+	}
+}
+
+// NewGenerator returns a new Generator configured by opts.
+func NewGenerator(opts ...GeneratorOption) *Generator {
+	g := &Generator{
+		clock: systemClock{},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// NewGeneratorWithNode returns a Generator that embeds node into the ULIDs it generates.
+// The node is placed in the first two bytes of the random component (bytes 6 and 7),
+// and the remaining 64 bits are filled with cryptographically secure random numbers.
+//
+// This reduces the entropy of the random component from 80 bits to 64 bits.
+// ULIDs generated in the same millisecond with the same node are distinguished only by
+// the 64 random bits, so generating n such ULIDs collides with a probability of about n^2/2^65.
+// Use a distinct node for each host to keep ULIDs from different hosts from colliding.
+func NewGeneratorWithNode(node [2]byte, opts ...GeneratorOption) *Generator {
+	g := NewGenerator(opts...)
+	g.node = node
+	g.hasNode = true
+	return g
+}
+
+// NewFastGenerator returns a Generator that fills the random component with
+// a ChaCha8 pseudo-random number generator from math/rand/v2, seeded once from crypto/rand.
+// It is faster than the default cryptographically secure source,
+// but the random components of its ULIDs are predictable to anyone who learns the internal state.
+// Use it only for IDs that need not be unguessable.
+func NewFastGenerator(opts ...GeneratorOption) *Generator {
+	var seed [32]byte
+	readRandom(seed[:])
+	g := NewGenerator(opts...)
+	g.entropy = mathrand.NewChaCha8(seed)
+	return g
+}
+
+// NewMonotonicAt returns a Generator whose clock is pinned at startMs
+// and whose last ULID is seeded with startMs and entropy.
+// [Generator.MakeMonotonic] increments the random component from the seed,
+// so the first call returns the ULID immediately after the seed.
+// Generators created with the same arguments produce the same sequence,
+// which is useful for replaying historical events and for test fixtures.
+func NewMonotonicAt(startMs int64, entropy [10]byte) *Generator {
+	g := NewGenerator(WithClock(NewManualClock(time.UnixMilli(startMs))))
+	g.last.SetTime(startMs)
+	copy(g.last[6:], entropy[:])
+	g.count = 1
+	return g
+}
+
+// NewTickerGenerator returns a Generator whose clock starts at the current time
+// and advances by interval each time a ULID is generated,
+// so successive ULIDs have evenly spaced time components without sleeping.
+// After the construction, it is independent of the wall clock.
+// The time component has millisecond precision; interval should be a multiple of [time.Millisecond].
+func NewTickerGenerator(interval time.Duration) *Generator {
+	return NewGenerator(WithClock(&tickerClock{
+		now:      time.UnixMilli(time.Now().UnixMilli()),
+		interval: interval,
+	}))
+}
+
+// SetEntropy replaces the source of the random component with r, for rotating the source without recreating g.
+// If r is nil, crypto/rand is used.
+// The replacement is made under the lock of g, so each ULID is filled entirely from either the old or the new source.
+func (g *Generator) SetEntropy(r io.Reader) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.entropy = r
+}
+
+// LocalMonotonic returns a function that generates monotonically increasing ULIDs
+// with a private [Generator], for goroutines that need monotonicity without sharing a Generator.
+// Call LocalMonotonic once in each goroutine and reuse the returned function.
+// ULIDs from different functions are not ordered with respect to each other.
+//
+// Like the package-level [MakeMonotonic], the function waits for the next millisecond
+// when the random component overflows.
+func LocalMonotonic() func() ULID {
+	g := NewGenerator()
+	return func() ULID {
+		for {
+			id, err := g.MakeMonotonic()
+			if err == nil {
+				return id
+			}
+			// overflow: wait for the next millisecond
+			time.Sleep(time.Until(time.UnixMilli(g.LastTime() + 1)))
+		}
+	}
+}
+
+// now returns the current time in Unix milliseconds.
+func (g *Generator) now() int64 {
+	return g.clock.Now().UnixMilli()
+}
+
+// random returns the part of id that is filled with random bytes.
+func (g *Generator) random(id *ULID) []byte {
+	if g.hasNode {
+		id[6] = g.node[0]
+		id[7] = g.node[1]
+		return id[8:]
+	}
+	return id[6:]
+}
+
+// readEntropy fills b with random bytes. g.mu must be held.
+func (g *Generator) readEntropy(b []byte) {
+	if g.entropy == nil {
+		readRandom(b)
+		return
+	}
+
+	// read into the scratch buffer to keep b non-escaping.
+	buf := g.buf[:len(b)]
+	if _, err := io.ReadFull(g.entropy, buf); err != nil {
+		panic(err)
+	}
+	copy(b, buf)
+}
+
+// Make returns a ULID with the current time in Unix milliseconds and a random component.
+func (g *Generator) Make() ULID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.now()
+	if g.floor && !g.made.IsZero() && ms <= g.made.Time() {
+		if id, ok := g.increment(g.made); ok {
+			g.made = id
+			return id
+		}
+		ms = g.made.Time() + 1
+	}
+
+	var id ULID
+	id.SetTime(ms)
+	g.readEntropy(g.random(&id))
+	if g.floor {
+		g.made = id
+	}
+	return id
+}
+
+// MakeMonotonic returns a ULID with the current time in Unix milliseconds and a random component.
+// It guarantees that the ULIDs generated by g are monotonically increasing, even if the time component is the same.
+// If the clock goes backward, the time component of the last ULID is reused.
+//
+// Unlike the package-level [MakeMonotonic], it does not wait for the next millisecond
+// when the random component overflows; it returns [ErrMonotonicOverflow] instead.
+func (g *Generator) MakeMonotonic() (ULID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var ids [1]ULID
+	if err := g.fillMonotonic(ids[:]); err != nil {
+		return ULID{}, err
+	}
+	return ids[0], nil
+}
+
+// MakeBatch returns n ULIDs that share the same time component and have strictly increasing random components.
+// The ULIDs continue the monotonic sequence of [Generator.MakeMonotonic].
+// If the random component overflows within the batch, it returns [ErrMonotonicOverflow] and no ULIDs are issued.
+func (g *Generator) MakeBatch(n int) ([]ULID, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ids := make([]ULID, n)
+	if err := g.fillMonotonic(ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// fillMonotonic fills ids with the next ULIDs of the monotonic sequence, sharing the same time component.
+// ids must not be empty. g.mu must be held.
+func (g *Generator) fillMonotonic(ids []ULID) error {
+	if g.store != nil && !g.loaded {
+		last, err := g.store.LoadLast()
+		if err != nil {
+			return err
+		}
+		if !last.IsZero() {
+			g.last = last
+			g.count = 1
+		}
+		g.loaded = true
+	}
+
+	ms := g.now()
+	count := g.count
+	if count == 0 || ms > g.last.Time() {
+		var id ULID
+		id.SetTime(ms)
+		g.readEntropy(g.random(&id))
+		ids[0] = id
+		count = 1
+	} else {
+		// If the time has not advanced, increment the random component.
+		id, ok := g.increment(g.last)
+		if !ok {
+			return ErrMonotonicOverflow
+		}
+		ids[0] = id
+		count++
+	}
+	for i := 1; i < len(ids); i++ {
+		id, ok := g.increment(ids[i-1])
+		if !ok {
+			return ErrMonotonicOverflow
+		}
+		ids[i] = id
+		count++
+	}
+
+	last := ids[len(ids)-1]
+	if err := g.save(last); err != nil {
+		return err
+	}
+	g.last = last
+	g.count = count
+	return nil
+}
+
+// save saves id to the store if any. g.mu must be held.
+func (g *Generator) save(id ULID) error {
+	if g.store == nil {
+		return nil
+	}
+	return g.store.SaveLast(id)
+}
+
+// LastTime returns the time component in Unix milliseconds of the last ULID generated by [Generator.MakeMonotonic].
+// It returns 0 if no ULID has been generated yet.
+func (g *Generator) LastTime() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.last.Time()
+}
+
+// IssuedInLastMillisecond returns the number of ULIDs generated by [Generator.MakeMonotonic]
+// in the millisecond of the last ULID.
+// A large number indicates that the random component is close to overflow.
+func (g *Generator) IssuedInLastMillisecond() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.count
+}
+
+// RemainingThisMillisecond returns the number of times [Generator.MakeMonotonic] can increment
+// the random component of the last ULID before it returns [ErrMonotonicOverflow].
+// If no ULID has been generated yet, it returns the number of ULIDs a millisecond can hold, [MaxPerMillisecond].
+// For a Generator created by [NewGeneratorWithNode], only the low 64 bits of the random component are incremented,
+// so the number is at most 2^64.
+func (g *Generator) RemainingThisMillisecond() *big.Int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.count == 0 {
+		if g.hasNode {
+			return new(big.Int).Lsh(big.NewInt(1), 64)
+		}
+		return MaxPerMillisecond()
+	}
+
+	// the remaining count is the maximum minus the current value, that is, the bitwise complement.
+	var remaining [10]byte
+	for i, b := range g.last[6:] {
+		remaining[i] = ^b
+	}
+	if g.hasNode {
+		remaining[0], remaining[1] = 0, 0
+	}
+	return new(big.Int).SetBytes(remaining[:])
+}
+
+// increment increments the random part of id, keeping the node unchanged.
+func (g *Generator) increment(id ULID) (ULID, bool) {
+	if !g.hasNode {
+		return id.IncEntropy()
+	}
+	lo := binary.BigEndian.Uint64(id[8:])
+	lo++
+	if lo == 0 {
+		return id, false
+	}
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, true
+}
@@ -0,0 +1,498 @@
+package ulid
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	c := NewManualClock(time.UnixMilli(1469918176385))
+	if got := c.Now().UnixMilli(); got != 1469918176385 {
+		t.Fatalf("now=%d", got)
+	}
+	c.Add(time.Millisecond)
+	if got := c.Now().UnixMilli(); got != 1469918176386 {
+		t.Fatalf("now=%d", got)
+	}
+	c.Set(time.UnixMilli(0))
+	if got := c.Now().UnixMilli(); got != 0 {
+		t.Fatalf("now=%d", got)
+	}
+}
+
+func TestGenerator_Make(t *testing.T) {
+	randReader = zeroReader{}
+	t.Cleanup(func() { randReader = rand.Reader })
+
+	c := NewManualClock(time.UnixMilli(1469918176385))
+	g := NewGenerator(WithClock(c))
+	id := g.Make()
+	if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+}
+
+func TestGenerator_MakeMonotonic(t *testing.T) {
+	t.Run("increment", func(t *testing.T) {
+		randReader = zeroReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGenerator(WithClock(c))
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+		id, err = g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+
+		// the clock goes backward
+		c.Add(-time.Second)
+		id, err = g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		randReader = maxReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGenerator(WithClock(c))
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+		_, err = g.MakeMonotonic()
+		if !errors.Is(err, ErrMonotonicOverflow) {
+			t.Fatalf("err=%v", err)
+		}
+
+		// the overflow is resolved in the next millisecond
+		c.Add(time.Millisecond)
+		id, err = g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x82, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("overflow with node", func(t *testing.T) {
+		randReader = maxReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGeneratorWithNode([2]byte{0x12, 0x34}, WithClock(c))
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x12, 0x34, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+		_, err = g.MakeMonotonic()
+		if !errors.Is(err, ErrMonotonicOverflow) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestWithTimeFloor(t *testing.T) {
+	c := NewManualClock(time.UnixMilli(1469918176385))
+	g := NewGenerator(WithClock(c), WithTimeFloor())
+
+	prev := g.Make()
+	check := func() {
+		t.Helper()
+		id := g.Make()
+		if id.Time() < prev.Time() {
+			t.Fatalf("the time component went backward: %d < %d", id.Time(), prev.Time())
+		}
+		if id.Compare(prev) <= 0 {
+			t.Fatalf("%v is not greater than %v", id, prev)
+		}
+		prev = id
+	}
+
+	// the clock stalls.
+	for range 10 {
+		check()
+	}
+
+	// the clock is stepped backward.
+	c.Add(-time.Second)
+	for range 10 {
+		check()
+		c.Add(time.Millisecond)
+	}
+	if prev.Time() != 1469918176385 {
+		t.Fatalf("time=%d", prev.Time())
+	}
+
+	// the clock recovers.
+	c.Add(time.Second)
+	check()
+	if prev.Time() != c.Now().UnixMilli() {
+		t.Fatalf("time=%d", prev.Time())
+	}
+}
+
+func TestWithTimeFloor_Overflow(t *testing.T) {
+	randReader = maxReader{}
+	t.Cleanup(func() { randReader = rand.Reader })
+
+	c := NewManualClock(time.UnixMilli(1469918176385))
+	g := NewGenerator(WithClock(c), WithTimeFloor())
+	prev := g.Make()
+	c.Add(-time.Second)
+	id := g.Make()
+	if id.Compare(prev) <= 0 {
+		t.Fatalf("%v is not greater than %v", id, prev)
+	}
+	if id.Time() != 1469918176386 {
+		t.Fatalf("time=%d", id.Time())
+	}
+}
+
+func TestGenerator_MakeBatch(t *testing.T) {
+	t.Run("batch", func(t *testing.T) {
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGenerator(WithClock(c))
+		ids, err := g.MakeBatch(1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1000 {
+			t.Fatalf("len=%d", len(ids))
+		}
+		for i, id := range ids {
+			if id.Time() != 1469918176385 {
+				t.Fatalf("ids[%d].Time()=%d", i, id.Time())
+			}
+			if i > 0 && ids[i-1].Compare(id) >= 0 {
+				t.Fatalf("ids[%d]=%v is not greater than ids[%d]=%v", i, id, i-1, ids[i-1])
+			}
+		}
+
+		// the monotonic sequence continues after the batch.
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != ids[999].Next() {
+			t.Fatalf("want %v, got %v", ids[999].Next(), id)
+		}
+		if g.IssuedInLastMillisecond() != 1001 {
+			t.Fatalf("count=%d", g.IssuedInLastMillisecond())
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		entropy := [10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0}
+		g := NewMonotonicAt(1469918176385, entropy)
+		if _, err := g.MakeBatch(16); !errors.Is(err, ErrMonotonicOverflow) {
+			t.Fatalf("err=%v", err)
+		}
+
+		// the failed batch must not consume the sequence.
+		ids, err := g.MakeBatch(15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids[14] != MaxForTime(1469918176385) {
+			t.Fatalf("ids[14]=%v", ids[14])
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		g := NewGenerator()
+		ids, err := g.MakeBatch(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 0 {
+			t.Fatalf("len=%d", len(ids))
+		}
+	})
+}
+
+func TestGenerator_LastTime(t *testing.T) {
+	c := NewManualClock(time.UnixMilli(1469918176385))
+	g := NewGenerator(WithClock(c))
+	if g.LastTime() != 0 || g.IssuedInLastMillisecond() != 0 {
+		t.Fatalf("last=%d issued=%d", g.LastTime(), g.IssuedInLastMillisecond())
+	}
+
+	for i := range 1000 {
+		if _, err := g.MakeMonotonic(); err != nil {
+			t.Fatal(err)
+		}
+		if got := g.IssuedInLastMillisecond(); got != uint64(i+1) {
+			t.Fatalf("issued=%d, want %d", got, i+1)
+		}
+	}
+	if g.LastTime() != 1469918176385 {
+		t.Fatalf("last=%d", g.LastTime())
+	}
+
+	// the counter is reset in the next millisecond
+	c.Add(time.Millisecond)
+	if _, err := g.MakeMonotonic(); err != nil {
+		t.Fatal(err)
+	}
+	if g.LastTime() != 1469918176386 || g.IssuedInLastMillisecond() != 1 {
+		t.Fatalf("last=%d issued=%d", g.LastTime(), g.IssuedInLastMillisecond())
+	}
+}
+
+func TestNewGeneratorWithNode(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		randReader = maxReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+		g := NewGeneratorWithNode([2]byte{0x12, 0x34})
+		id := g.Make()
+		if id != (ULID{0x00, 0xdc, 0x6a, 0xcf, 0xac, 0x00, 0x12, 0x34, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	// Test that the node is preserved and the rest of the random component varies.
+	g := NewGeneratorWithNode([2]byte{0x12, 0x34})
+	seen := make(map[[8]byte]struct{}, 10000)
+	for range 10000 {
+		id := g.Make()
+		if id[6] != 0x12 || id[7] != 0x34 {
+			t.Fatalf("node is not preserved: %x", [16]byte(id))
+		}
+		random := [8]byte(id[8:])
+		if _, ok := seen[random]; ok {
+			t.Fatalf("duplicate random component: %x", [16]byte(id))
+		}
+		seen[random] = struct{}{}
+	}
+}
+
+func TestNewFastGenerator(t *testing.T) {
+	g := NewFastGenerator()
+	seen := make(map[ULID]struct{}, 10000)
+	for range 10000 {
+		id := g.Make()
+		if _, ok := seen[id]; ok {
+			t.Fatalf("duplicate ULID: %v", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func TestGenerator_SetEntropy(t *testing.T) {
+	g := NewGenerator()
+	g.SetEntropy(zeroReader{})
+	if id := g.Make(); !id.SameEntropy(Zero) {
+		t.Fatalf("id=%v", id)
+	}
+	g.SetEntropy(maxReader{})
+	if id := g.Make(); !id.SameEntropy(MaxForTime(0)) {
+		t.Fatalf("id=%v", id)
+	}
+}
+
+func TestGenerator_SetEntropy_Concurrent(t *testing.T) {
+	g := NewGenerator()
+	g.SetEntropy(zeroReader{})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 1000 {
+				id := g.Make()
+				if !id.SameEntropy(Zero) && !id.SameEntropy(MaxForTime(0)) {
+					t.Errorf("the random component is mixed: %x", [16]byte(id))
+					return
+				}
+			}
+		})
+	}
+	for i := range 1000 {
+		if i%2 == 0 {
+			g.SetEntropy(maxReader{})
+		} else {
+			g.SetEntropy(zeroReader{})
+		}
+	}
+	wg.Wait()
+}
+
+func TestLocalMonotonic(t *testing.T) {
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			next := LocalMonotonic()
+			prev := next()
+			for range 10000 {
+				id := next()
+				if id.Compare(prev) <= 0 {
+					t.Errorf("%v is not greater than %v", id, prev)
+					return
+				}
+				prev = id
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestNewMonotonicAt(t *testing.T) {
+	entropy := [10]byte{0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b, 0xff, 0xfe}
+	g1 := NewMonotonicAt(1469918176385, entropy)
+	g2 := NewMonotonicAt(1469918176385, entropy)
+
+	want := ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b, 0xff, 0xff}
+	for i := range 100 {
+		id1, err := g1.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id2, err := g2.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id1 != id2 {
+			t.Fatalf("%d: %v != %v", i, id1, id2)
+		}
+		if i == 0 && id1 != want {
+			t.Fatalf("want %x, got %x", [16]byte(want), [16]byte(id1))
+		}
+		if i == 1 && id1 != want.Next() {
+			t.Fatalf("want %x, got %x", [16]byte(want.Next()), [16]byte(id1))
+		}
+	}
+}
+
+func TestNewTickerGenerator(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		start := time.Now().UnixMilli()
+		g := NewTickerGenerator(5 * time.Second)
+
+		prev := g.Make()
+		if prev.Time() != start {
+			t.Fatalf("want %d, got %d", start, prev.Time())
+		}
+		for range 100 {
+			time.Sleep(time.Millisecond) // the wall clock must not affect the generator
+			id := g.Make()
+			if d := id.Time() - prev.Time(); d != 5000 {
+				t.Fatalf("want 5000, got %d", d)
+			}
+			prev = id
+		}
+	})
+}
+
+func TestGenerator_RemainingThisMillisecond(t *testing.T) {
+	t.Run("fresh", func(t *testing.T) {
+		g := NewGenerator()
+		if got := g.RemainingThisMillisecond(); got.Cmp(MaxPerMillisecond()) != 0 {
+			t.Fatalf("want %s, got %s", MaxPerMillisecond(), got)
+		}
+	})
+
+	t.Run("near overflow", func(t *testing.T) {
+		entropy := [10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0}
+		g := NewMonotonicAt(1469918176385, entropy)
+		if got := g.RemainingThisMillisecond(); got.Int64() != 15 {
+			t.Fatalf("want 15, got %s", got)
+		}
+		for range 5 {
+			if _, err := g.MakeMonotonic(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := g.RemainingThisMillisecond(); got.Int64() != 10 {
+			t.Fatalf("want 10, got %s", got)
+		}
+		for range 10 {
+			if _, err := g.MakeMonotonic(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := g.RemainingThisMillisecond(); got.Sign() != 0 {
+			t.Fatalf("want 0, got %s", got)
+		}
+		if _, err := g.MakeMonotonic(); !errors.Is(err, ErrMonotonicOverflow) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("zero entropy", func(t *testing.T) {
+		randReader = zeroReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGenerator(WithClock(c))
+		for range 3 {
+			if _, err := g.MakeMonotonic(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := MaxPerMillisecond()
+		want.Sub(want, big.NewInt(3))
+		if got := g.RemainingThisMillisecond(); got.Cmp(want) != 0 {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+	})
+
+	t.Run("node", func(t *testing.T) {
+		randReader = zeroReader{}
+		t.Cleanup(func() { randReader = rand.Reader })
+
+		c := NewManualClock(time.UnixMilli(1469918176385))
+		g := NewGeneratorWithNode([2]byte{0xff, 0xff}, WithClock(c))
+		if _, err := g.MakeMonotonic(); err != nil {
+			t.Fatal(err)
+		}
+		want := new(big.Int).SetUint64(math.MaxUint64)
+		if got := g.RemainingThisMillisecond(); got.Cmp(want) != 0 {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+	})
+}
+
+func BenchmarkFastGenerator(b *testing.B) {
+	g := NewFastGenerator()
+	b.ReportAllocs()
+	for b.Loop() {
+		runtime.KeepAlive(g.Make())
+	}
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	g := NewGenerator()
+	b.ReportAllocs()
+	for b.Loop() {
+		runtime.KeepAlive(g.Make())
+	}
+}
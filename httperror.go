@@ -0,0 +1,61 @@
+package ulid
+
+import "errors"
+
+// HTTP status codes returned by [HTTPError.StatusCode].
+// They are defined here to avoid depending on net/http.
+const (
+	statusBadRequest          = 400
+	statusInternalServerError = 500
+	statusServiceUnavailable  = 503
+)
+
+// An HTTPError is an error with a hint of the HTTP status code for web handlers.
+// See [WrapHTTP].
+type HTTPError struct {
+	Err  error
+	Code int
+}
+
+// WrapHTTP wraps err in an [*HTTPError] so that middleware can map it to an HTTP response uniformly.
+// The status code is chosen by the kind of err:
+//   - 400 Bad Request for errors of parsing untrusted input, such as [ErrInvalidSize],
+//     [ErrInvalidCharacter], [ErrOverflow], [ErrChecksum], [ErrZero] and [ErrTooOld].
+//   - 503 Service Unavailable for [ErrMonotonicOverflow], which resolves in the next millisecond.
+//   - 500 Internal Server Error for the others.
+//
+// It returns nil if err is nil.
+// The original error is available by [errors.Is] and [errors.As] through the wrapper.
+func WrapHTTP(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := statusInternalServerError
+	switch {
+	case errors.Is(err, ErrInvalidSize),
+		errors.Is(err, ErrInvalidCharacter),
+		errors.Is(err, ErrOverflow),
+		errors.Is(err, ErrChecksum),
+		errors.Is(err, ErrZero),
+		errors.Is(err, ErrTooOld):
+		code = statusBadRequest
+	case errors.Is(err, ErrMonotonicOverflow):
+		code = statusServiceUnavailable
+	}
+	return &HTTPError{Err: err, Code: code}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status code for e.
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
@@ -0,0 +1,60 @@
+package ulid
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrapHTTP(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{ErrInvalidSize, 400},
+		{ErrEmpty, 400},
+		{ErrInvalidCharacter, 400},
+		{ErrOverflow, 400},
+		{ErrChecksum, 400},
+		{ErrZero, 400},
+		{ErrTooOld, 400},
+		{fmt.Errorf("ulid: environment variable ID: %w", ErrInvalidCharacter), 400},
+		{ErrMonotonicOverflow, 503},
+		{errors.New("unknown"), 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			err := WrapHTTP(tt.err)
+			var coder interface{ StatusCode() int }
+			if !errors.As(err, &coder) {
+				t.Fatalf("%T does not implement StatusCode", err)
+			}
+			if got := coder.StatusCode(); got != tt.want {
+				t.Fatalf("want %d, got %d", tt.want, got)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("the original error is lost: %v", err)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Fatalf("want %q, got %q", tt.err.Error(), err.Error())
+			}
+		})
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		_, err := Parse("not a ulid")
+		var herr *HTTPError
+		if !errors.As(WrapHTTP(err), &herr) {
+			t.Fatal("not an HTTPError")
+		}
+		if herr.StatusCode() != 400 {
+			t.Fatalf("status=%d", herr.StatusCode())
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := WrapHTTP(nil); err != nil {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
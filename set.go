@@ -0,0 +1,48 @@
+package ulid
+
+import (
+	"iter"
+	"slices"
+)
+
+// A Set is a set of ULIDs backed by a sorted slice.
+// The zero value is an empty set ready to use.
+// A Set is not safe for concurrent use by multiple goroutines.
+type Set struct {
+	ids []ULID
+}
+
+// Add adds id to the set.
+// It reports whether id was newly added.
+func (s *Set) Add(id ULID) bool {
+	i, found := slices.BinarySearchFunc(s.ids, id, ULID.Compare)
+	if found {
+		return false
+	}
+	s.ids = slices.Insert(s.ids, i, id)
+	return true
+}
+
+// Contains reports whether id is in the set.
+func (s *Set) Contains(id ULID) bool {
+	_, found := slices.BinarySearchFunc(s.ids, id, ULID.Compare)
+	return found
+}
+
+// Len returns the number of ULIDs in the set.
+func (s *Set) Len() int {
+	return len(s.ids)
+}
+
+// Range returns an iterator over the ULIDs in the set that are in [lo, hi), in ascending order.
+// The set must not be modified during the iteration.
+func (s *Set) Range(lo, hi ULID) iter.Seq[ULID] {
+	return func(yield func(ULID) bool) {
+		i, _ := slices.BinarySearchFunc(s.ids, lo, ULID.Compare)
+		for ; i < len(s.ids) && s.ids[i].Compare(hi) < 0; i++ {
+			if !yield(s.ids[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ulid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	ids := []ULID{
+		MinForTime(3),
+		MinForTime(1),
+		MaxForTime(2),
+		MinForTime(2),
+		MinForTime(4),
+	}
+
+	var s Set
+	for _, id := range ids {
+		if !s.Add(id) {
+			t.Fatalf("%v is not added", id)
+		}
+	}
+	if s.Add(MinForTime(1)) {
+		t.Fatal("duplicated ULID is added")
+	}
+	if s.Len() != 5 {
+		t.Fatalf("len=%d", s.Len())
+	}
+
+	for _, id := range ids {
+		if !s.Contains(id) {
+			t.Fatalf("%v is not found", id)
+		}
+	}
+	if s.Contains(MinForTime(5)) {
+		t.Fatal("unexpected ULID is found")
+	}
+
+	// Test that the ULIDs are iterated in ascending order.
+	got := slices.Collect(s.Range(Zero, MaxForTime(0xFFFFFFFFFFFF)))
+	want := []ULID{MinForTime(1), MinForTime(2), MaxForTime(2), MinForTime(3), MinForTime(4)}
+	if !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSet_Range(t *testing.T) {
+	var s Set
+	for ms := range int64(10) {
+		s.Add(MinForTime(ms))
+	}
+
+	t.Run("half-open", func(t *testing.T) {
+		got := slices.Collect(s.Range(MinForTime(3), MinForTime(6)))
+		want := []ULID{MinForTime(3), MinForTime(4), MinForTime(5)}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("bounds not in the set", func(t *testing.T) {
+		got := slices.Collect(s.Range(MaxForTime(3), MaxForTime(5)))
+		want := []ULID{MinForTime(4), MinForTime(5)}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got := slices.Collect(s.Range(MinForTime(6), MinForTime(3)))
+		if len(got) != 0 {
+			t.Fatalf("got %v", got)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var got []ULID
+		for id := range s.Range(Zero, MinForTime(10)) {
+			got = append(got, id)
+			if len(got) == 2 {
+				break
+			}
+		}
+		want := []ULID{MinForTime(0), MinForTime(1)}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+}
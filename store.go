@@ -0,0 +1,101 @@
+package ulid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// A Store persists the last ULID issued by a [Generator],
+// so that the Generator never reuses a ULID even after the process restarts.
+// See [WithStore].
+type Store interface {
+	// LoadLast returns the last ULID saved by SaveLast.
+	// It returns [Zero] if no ULID has been saved yet.
+	LoadLast() (ULID, error)
+
+	// SaveLast saves id as the last issued ULID.
+	SaveLast(id ULID) error
+}
+
+// WithStore returns a GeneratorOption that makes [Generator.MakeMonotonic] persist each ULID to s.
+// The last ULID is loaded from s on the first call of MakeMonotonic,
+// and the following ULIDs are strictly greater than it, even if the clock went backward while the process was stopped.
+// A ULID is returned only after it is saved successfully.
+//
+// [Generator.Make] does not use the store.
+func WithStore(s Store) GeneratorOption {
+	return func(g *Generator) {
+		g.store = s
+	}
+}
+
+// A FileStore is a [Store] that saves the last ULID to a file in the text encoding.
+// The file is replaced atomically by renaming a temporary file in the same directory,
+// and the directory is synced so that the rename survives a crash.
+// A FileStore must not be shared by multiple processes.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a FileStore that saves the last ULID to the file named path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LoadLast implements the [Store] interface.
+// It returns [Zero] if the file does not exist.
+func (s *FileStore) LoadLast() (ULID, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Zero, nil
+	}
+	if err != nil {
+		return ULID{}, err
+	}
+	id, err := parse(bytes.TrimSpace(data))
+	if err != nil {
+		return ULID{}, fmt.Errorf("ulid: file %s: %w", s.path, err)
+	}
+	return id, nil
+}
+
+// SaveLast implements the [Store] interface.
+func (s *FileStore) SaveLast(id ULID) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	buf := id.AppendTextCase(make([]byte, 0, EncodedSize+1), true)
+	buf = append(buf, '\n')
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return err
+	}
+
+	// sync the directory to make the rename durable.
+	dir, err := os.Open(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
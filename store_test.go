@@ -0,0 +1,133 @@
+package ulid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last")
+	s := NewFileStore(path)
+
+	last, err := s.LoadLast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !last.IsZero() {
+		t.Fatalf("want Zero, got %v", last)
+	}
+
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if err := s.SaveLast(id); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "01ARZ3NDEKTSV4RRFFQ69G5FAV\n" {
+		t.Fatalf("data=%q", data)
+	}
+
+	last, err = s.LoadLast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != id {
+		t.Fatalf("want %v, got %v", id, last)
+	}
+
+	// no temporary files are left behind.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries=%v", entries)
+	}
+}
+
+func TestFileStore_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last")
+	if err := os.WriteFile(path, []byte("corrupted"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileStore(path)
+	if _, err := s.LoadLast(); !errors.Is(err, ErrInvalidSize) {
+		t.Fatalf("err=%v", err)
+	}
+
+	g := NewGenerator(WithStore(s))
+	if _, err := g.MakeMonotonic(); !errors.Is(err, ErrInvalidSize) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestGenerator_WithStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last")
+	c := NewManualClock(time.UnixMilli(1469918176385))
+
+	g := NewGenerator(WithClock(c), WithStore(NewFileStore(path)))
+	var last ULID
+	for range 10 {
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = id
+	}
+
+	// simulate a restart with the clock jumped backward.
+	c.Add(-time.Hour)
+	g = NewGenerator(WithClock(c), WithStore(NewFileStore(path)))
+	for range 10 {
+		id, err := g.MakeMonotonic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.Compare(last) <= 0 {
+			t.Fatalf("%v is not greater than %v", id, last)
+		}
+		last = id
+	}
+
+	// restart again after the clock recovered.
+	c.Add(2 * time.Hour)
+	g = NewGenerator(WithClock(c), WithStore(NewFileStore(path)))
+	id, err := g.MakeMonotonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Compare(last) <= 0 {
+		t.Fatalf("%v is not greater than %v", id, last)
+	}
+	if id.Time() != c.Now().UnixMilli() {
+		t.Fatalf("time=%d", id.Time())
+	}
+}
+
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) LoadLast() (ULID, error) {
+	return Zero, nil
+}
+
+func (s failingStore) SaveLast(id ULID) error {
+	return s.err
+}
+
+func TestGenerator_WithStore_SaveError(t *testing.T) {
+	errSave := errors.New("save error")
+	g := NewGenerator(WithStore(failingStore{err: errSave}))
+	if _, err := g.MakeMonotonic(); !errors.Is(err, errSave) {
+		t.Fatalf("err=%v", err)
+	}
+	if g.IssuedInLastMillisecond() != 0 {
+		t.Fatalf("the unsaved ULID was recorded: %d", g.IssuedInLastMillisecond())
+	}
+}
@@ -3,11 +3,22 @@ package ulid
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql/driver"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -29,6 +40,10 @@ A ULID is a 16 byte Universally Unique Lexicographically Sortable Identifier
 	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 	|                       32_bit_uint_random                      |
 	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+Sorting ULIDs by [ULID.Compare], by their binary encoding with [bytes.Compare],
+and by their canonical (upper case) text encoding with [strings.Compare] always produces the same order.
+This does not hold for the lower case text encoding, because digits sort after upper case letters but before lower case ones.
 */
 type ULID [16]byte
 
@@ -38,15 +53,31 @@ var Zero ULID
 // Errors returned by the Parse function.
 var ErrInvalidSize = errors.New("ulid: invalid size")
 
+// ErrEmpty is returned by the Parse function when the input is empty.
+// It wraps [ErrInvalidSize], so errors.Is(err, ErrInvalidSize) also reports true for empty input.
+var ErrEmpty = fmt.Errorf("ulid: empty input: %w", ErrInvalidSize)
+
 // Errors returned by the Parse function.
 var ErrInvalidCharacter = errors.New("ulid: invalid character")
 
 // Errors returned by the Parse function.
 var ErrOverflow = errors.New("ulid: overflow")
 
+// ErrZero is returned by UnmarshalBinaryStrict when the data is the zero ULID.
+var ErrZero = errors.New("ulid: zero value")
+
+// ErrChecksum is returned by ParseWithChecksum when the checksum character does not match.
+var ErrChecksum = errors.New("ulid: checksum mismatch")
+
+// ErrTooOld is returned by ParseAfter when the time component is before the cutoff.
+var ErrTooOld = errors.New("ulid: too old")
+
 // EncodedSize is the size of a ULID when encoded to text.
 const EncodedSize = 26
 
+// BinarySize is the size of a ULID when encoded to binary.
+const BinarySize = 16
+
 // for testing
 var randReader = rand.Reader
 
@@ -58,13 +89,69 @@ var (
 )
 
 // Make returns a ULID with the current time in Unix milliseconds and a random component.
+// It does not allocate.
 func Make() ULID {
 	var id ULID
 	id.SetTime(time.Now().UnixMilli())
-	if _, err := io.ReadFull(randReader, id[6:]); err != nil {
+	readRandom(id[6:])
+	return id
+}
+
+// MakeSecond returns a ULID with the current time truncated to the second and a random component.
+// All ULIDs made in the same second share the time component, which helps coarse range scans.
+// The random component still has the full 80 bits.
+// It does not allocate.
+func MakeSecond() ULID {
+	var id ULID
+	id.SetTime(time.Now().Unix() * 1000)
+	readRandom(id[6:])
+	return id
+}
+
+// GenerateSpread returns n ULIDs whose timestamps are evenly distributed from start to end, both inclusive.
+// The random components are filled with cryptographically secure random numbers,
+// and the result is sorted in ascending order.
+// It is useful for generating time-series test data.
+// It returns nil if n <= 0, and panics if start is after end.
+func GenerateSpread(n int, start, end time.Time) []ULID {
+	if n <= 0 {
+		return nil
+	}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+	if startMs > endMs {
+		panic("ulid: start time must not be after end time")
+	}
+
+	span := uint64(endMs - startMs)
+	ids := make([]ULID, n)
+	for i := range ids {
+		var offset uint64
+		if n > 1 {
+			// offset = span * i / (n - 1), without overflow
+			hi, lo := bits.Mul64(span, uint64(i))
+			offset, _ = bits.Div64(hi, lo, uint64(n-1))
+		}
+		ids[i].SetTime(startMs + int64(offset))
+		readRandom(ids[i][6:])
+	}
+	slices.SortFunc(ids, ULID.Compare)
+	return ids
+}
+
+// readRandom fills b with random bytes read from randReader.
+func readRandom(b []byte) {
+	// Escape analysis can't see through a potentially overridden randReader,
+	// so we special-case the default reader to keep b non-escaping,
+	// and in the general case we read into a heap buffer and copy from it.
+	if randReader == rand.Reader {
+		rand.Read(b)
+		return
+	}
+	bb := make([]byte, len(b))
+	if _, err := io.ReadFull(randReader, bb); err != nil {
 		panic(err)
 	}
-	return id
+	copy(b, bb)
 }
 
 // MakeMonotonic returns a ULID with the current time in Unix milliseconds and a random component.
@@ -81,9 +168,7 @@ START:
 	if m > millis {
 		millis = m
 		id.SetTime(m)
-		if _, err := io.ReadFull(randReader, id[6:]); err != nil {
-			panic(err)
-		}
+		readRandom(id[6:])
 		randHi = binary.BigEndian.Uint16(id[6:])
 		randLo = binary.BigEndian.Uint64(id[8:])
 		return id
@@ -106,6 +191,273 @@ START:
 	return id
 }
 
+// Derive returns a deterministic ULID derived from the namespace ns and name,
+// in the same manner as name-based UUIDs (version 5).
+// All 16 bytes are taken from the SHA-256 digest of ns followed by name,
+// so the same ns and name always yield the same ULID.
+//
+// The time component of the derived ULID is a part of the digest and does not represent a meaningful time.
+func Derive(ns ULID, name []byte) ULID {
+	h := sha256.New()
+	h.Write(ns[:])
+	h.Write(name)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+
+	var id ULID
+	copy(id[:], sum[:])
+	return id
+}
+
+// MakeContentAddressed returns a ULID with the current time in Unix milliseconds
+// and the random component taken from the first 80 bits of the SHA-256 digest of payload.
+// Identical payloads made in the same millisecond intentionally yield the same ULID,
+// which helps deduplicate them; in different milliseconds, they share only the random component.
+// Different payloads collide only if their digests share the first 80 bits.
+// The random component is predictable from payload, so the ULID must not be used where unguessability matters.
+func MakeContentAddressed(payload []byte) ULID {
+	sum := sha256.Sum256(payload)
+	var id ULID
+	id.SetTime(time.Now().UnixMilli())
+	copy(id[6:], sum[:])
+	return id
+}
+
+// Timestamp returns the time component of id as a [time.Time].
+func (id ULID) Timestamp() time.Time {
+	return time.UnixMilli(id.Time())
+}
+
+// Date returns the date in UTC when id was generated.
+func (id ULID) Date() (year int, month time.Month, day int) {
+	return id.Timestamp().UTC().Date()
+}
+
+// Parts returns the time component in Unix milliseconds and the random component of id.
+func (id ULID) Parts() (ms int64, entropy [10]byte) {
+	return id.Time(), [10]byte(id[6:])
+}
+
+// TimeBytes returns a copy of the time component of id, the first 6 bytes in big-endian order.
+// Modifying the returned slice does not affect id.
+func (id ULID) TimeBytes() []byte {
+	return slices.Clone(id[:6])
+}
+
+// Entropy returns a copy of the random component of id, the last 10 bytes.
+// Modifying the returned slice does not affect id.
+func (id ULID) Entropy() []byte {
+	return slices.Clone(id[6:])
+}
+
+// PartitionPath formats the time component of id in UTC with layout, as accepted by [time.Time.Format].
+// It is useful for building date-partitioned storage prefixes such as "2006/01/02/15".
+// Only the time component determines the result.
+func (id ULID) PartitionPath(layout string) string {
+	return time.UnixMilli(id.Time()).UTC().Format(layout)
+}
+
+// Before reports whether the time component of id is before t, with millisecond precision.
+// The random component is ignored.
+func (id ULID) Before(t time.Time) bool {
+	return id.Time() < t.UnixMilli()
+}
+
+// OlderThan reports whether id was generated more than d ago.
+// The current time is read from the wall clock by [time.Now],
+// because the time component of a ULID is a wall clock time and has no monotonic reading.
+func (id ULID) OlderThan(d time.Duration) bool {
+	return time.Since(time.UnixMilli(id.Time())) > d
+}
+
+// PlausibleMake reports whether id plausibly came from [Make] recently:
+// its time component is within maxSkew of the current time in either direction,
+// and its random component is neither all zero bits nor all one bits.
+// It is a cheap heuristic for filtering garbage in ingestion pipelines, not authentication;
+// anyone can construct a ULID that passes it.
+func (id ULID) PlausibleMake(maxSkew time.Duration) bool {
+	// compare in milliseconds; converting the gap to a Duration may overflow.
+	d := time.Now().UnixMilli() - id.Time()
+	skew := maxSkew.Milliseconds()
+	if d < -skew || d > skew {
+		return false
+	}
+	return !id.SameEntropy(Zero) && !id.SameEntropy(MaxForTime(0))
+}
+
+// FromSequence returns a ULID with the time component ms and seq in the low 64 bits.
+// The high 16 bits of the random component are zero.
+// It maps a legacy sequential ID to a ULID deterministically; the result is not random at all.
+// The sequence can be recovered with binary.BigEndian.Uint64(id[8:]).
+func FromSequence(seq uint64, ms int64) ULID {
+	var id ULID
+	id.SetTime(ms)
+	binary.BigEndian.PutUint64(id[8:], seq)
+	return id
+}
+
+// TimeTokenSize is the size of the time component encoded by [ULID.TimeToken].
+const TimeTokenSize = 10
+
+// TimeToken returns the time component of id encoded in 10 characters of Crockford's Base32,
+// which equals the first 10 characters of [ULID.String].
+// The tokens sort in the same order as the time components, so they are shorter keys for indexes that need only time ordering.
+// Use [ParseTimeToken] to parse it.
+func (id ULID) TimeToken() string {
+	buf := id.text()
+	return string(buf[:TimeTokenSize])
+}
+
+// ParseTimeToken parses a token encoded by [ULID.TimeToken] and returns the time component in Unix milliseconds.
+func ParseTimeToken(s string) (int64, error) {
+	if len(s) != TimeTokenSize {
+		return 0, ErrInvalidSize
+	}
+	buf := Zero.text()
+	copy(buf[:TimeTokenSize], s)
+	id, err := parse(buf[:])
+	if err != nil {
+		return 0, err
+	}
+	return id.Time(), nil
+}
+
+// CompactEntropySize is the size of the random component encoded by [ULID.CompactEntropy].
+const CompactEntropySize = 16
+
+// CompactEntropy returns the random component of id encoded in 16 characters of Crockford's Base32,
+// for systems that store the time component separately.
+// It is lossy: the time component is dropped and must be supplied to [FromCompactEntropy] to reconstruct the ULID.
+// The result equals the last 16 characters of [ULID.String].
+func (id ULID) CompactEntropy() string {
+	buf := id.text()
+	return string(buf[EncodedSize-CompactEntropySize:])
+}
+
+// FromCompactEntropy reconstructs a ULID from the random component s encoded by [ULID.CompactEntropy]
+// and the time component ms in Unix milliseconds.
+// It returns [ErrOverflow] if ms does not fit in 48 bits.
+func FromCompactEntropy(s string, ms int64) (ULID, error) {
+	if len(s) != CompactEntropySize {
+		return ULID{}, ErrInvalidSize
+	}
+	var id ULID
+	if err := id.SetTimeSafe(ms); err != nil {
+		return ULID{}, err
+	}
+	buf := id.text()
+	copy(buf[EncodedSize-CompactEntropySize:], s)
+	return parse(buf[:])
+}
+
+// Age returns the time elapsed since id was generated.
+// The current time is read from the wall clock by [time.Now].
+// It is negative if the time component of id is in the future.
+func (id ULID) Age() time.Duration {
+	return time.Since(time.UnixMilli(id.Time()))
+}
+
+// AgeString returns the age of id in a compact human-friendly format, such as "45s", "3m", "2h", or "5d".
+// The age is truncated to the largest unit that fits.
+// The current time is read from the wall clock by [time.Now].
+func (id ULID) AgeString() string {
+	d := id.Age()
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	switch {
+	case d < time.Second:
+		return sign + strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	case d < time.Minute:
+		return sign + strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	case d < time.Hour:
+		return sign + strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	case d < 24*time.Hour:
+		return sign + strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	}
+	return sign + strconv.FormatInt(int64(d/(24*time.Hour)), 10) + "d"
+}
+
+// FromInt64Pair returns the ULID whose high and low 64 bits are hi and lo in big-endian order.
+// The sign bits of hi and lo are just the most significant bits of each half.
+// It is the inverse of [ULID.Int64Pair].
+func FromInt64Pair(hi, lo int64) ULID {
+	var id ULID
+	binary.BigEndian.PutUint64(id[:8], uint64(hi))
+	binary.BigEndian.PutUint64(id[8:], uint64(lo))
+	return id
+}
+
+// Int64Pair returns the high and low 64 bits of id in big-endian order as signed integers,
+// for storing the ULID in two signed 64-bit integer columns.
+func (id ULID) Int64Pair() (hi, lo int64) {
+	return int64(binary.BigEndian.Uint64(id[:8])), int64(binary.BigEndian.Uint64(id[8:]))
+}
+
+// MinForTime returns the smallest ULID with the time component ms.
+// Its random component is all zero.
+func MinForTime(ms int64) ULID {
+	var id ULID
+	id.SetTime(ms)
+	return id
+}
+
+// MaxForTime returns the largest ULID with the time component ms.
+// Its random component is all one bits.
+func MaxForTime(ms int64) ULID {
+	id := ULID{6: 0xff, 7: 0xff, 8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff}
+	id.SetTime(ms)
+	return id
+}
+
+// FirstInMillis returns the smallest ULID in the same millisecond as id.
+// It is equivalent to MinForTime(id.Time()).
+func (id ULID) FirstInMillis() ULID {
+	return MinForTime(id.Time())
+}
+
+// LastInMillis returns the largest ULID in the same millisecond as id.
+// It is equivalent to MaxForTime(id.Time()).
+func (id ULID) LastInMillis() ULID {
+	return MaxForTime(id.Time())
+}
+
+// TimeRange returns the bounds of ULIDs generated from startMs to endMs in Unix milliseconds.
+// Both bounds are inclusive: lo is MinForTime(startMs) and hi is MaxForTime(endMs).
+// It panics if startMs > endMs.
+func TimeRange(startMs, endMs int64) (lo, hi ULID) {
+	if startMs > endMs {
+		panic("ulid: start time must not be after end time")
+	}
+	return MinForTime(startMs), MaxForTime(endMs)
+}
+
+// BucketBounds returns an iterator over the windows of stepMs milliseconds covering [startMs, endMs],
+// yielding the smallest and the largest ULIDs of each window, for chunked scans over a sorted store.
+// The last window is truncated at endMs if the range is not a multiple of stepMs.
+// It yields nothing if startMs > endMs, and panics if stepMs is not positive.
+func BucketBounds(startMs, endMs, stepMs int64) iter.Seq2[ULID, ULID] {
+	if stepMs <= 0 {
+		panic("ulid: stepMs must be positive")
+	}
+	return func(yield func(lo, hi ULID) bool) {
+		for ms := startMs; ms <= endMs; ms += stepMs {
+			last := endMs
+			if endMs-ms >= stepMs {
+				last = ms + stepMs - 1
+			}
+			if !yield(MinForTime(ms), MaxForTime(last)) {
+				return
+			}
+			if last == endMs {
+				return
+			}
+		}
+	}
+}
+
 // SetTime sets the time component of the ULID to the given Unix milliseconds.
 func (id *ULID) SetTime(ms int64) {
 	if ms < 0 || ms > 0xFFFFFFFFFFFF {
@@ -120,13 +472,247 @@ func (id *ULID) SetTime(ms int64) {
 	id[5] = byte(ms)
 }
 
+// SetTimeSafe is like [ULID.SetTime], but it returns [ErrOverflow] instead of panicking
+// if ms does not fit in the 48-bit time component; id is unchanged in that case.
+// Every 48-bit time component encodes to text starting with a character between 0 and 7,
+// so the resulting ULID always round-trips through [ULID.String] and [Parse].
+func (id *ULID) SetTimeSafe(ms int64) error {
+	if ms < 0 || ms > 0xFFFFFFFFFFFF {
+		return ErrOverflow
+	}
+	id.SetTime(ms)
+	return nil
+}
+
+// ClampTime returns a copy of id whose time component is clamped into [minMs, maxMs].
+// If the time component is less than minMs, it is replaced with minMs;
+// if it is greater than maxMs, it is replaced with maxMs.
+// The random component is preserved.
+// It panics if minMs > maxMs or either of them is out of the range of the time component, like [ULID.SetTime].
+func (id ULID) ClampTime(minMs, maxMs int64) ULID {
+	if minMs > maxMs {
+		panic("ulid: minMs must not be greater than maxMs")
+	}
+	id.SetTime(min(max(id.Time(), minMs), maxMs))
+	return id
+}
+
+// WithTimeAfter returns a copy of id whose time component is at least ref.Time()+1,
+// so that the result sorts after ref. The random component is preserved.
+// If the time component of id is already later than that of ref, id is returned unchanged.
+// It returns [ErrOverflow] if ref.Time()+1 does not fit in 48 bits.
+func (id ULID) WithTimeAfter(ref ULID) (ULID, error) {
+	if id.Time() > ref.Time() {
+		return id, nil
+	}
+	ms := ref.Time() + 1
+	if ms > 0xFFFFFFFFFFFF {
+		return ULID{}, ErrOverflow
+	}
+	id.SetTime(ms)
+	return id, nil
+}
+
 // Time returns the time component of the ULID as Unix milliseconds.
 func (id ULID) Time() int64 {
 	return int64(id[0])<<40 | int64(id[1])<<32 | int64(id[2])<<24 |
 		int64(id[3])<<16 | int64(id[4])<<8 | int64(id[5])
 }
 
+// UnixSeconds returns the time component of the ULID as Unix seconds, for coarse bucketing.
+// The milliseconds are truncated; since the time component is never negative, this is the floor of Time()/1000.
+func (id ULID) UnixSeconds() int64 {
+	return id.Time() / 1000
+}
+
+// CollisionProbability returns the probability that at least two of n ULIDs generated by [Make] in the same millisecond collide.
+// It is approximated by the birthday bound 1 - exp(-n(n-1)/2^81) for n random 80-bit values.
+// It helps to decide whether [MakeMonotonic] is necessary.
+func CollisionProbability(idsPerMillisecond int) float64 {
+	if idsPerMillisecond < 2 {
+		return 0
+	}
+	n := float64(idsPerMillisecond)
+	return -math.Expm1(-n * (n - 1) / (1 << 81))
+}
+
+// MaxPerMillisecond returns the number of distinct ULIDs in a millisecond, 2^80.
+// [MakeMonotonic] and [Generator.MakeMonotonic] can issue at most this many ULIDs in a millisecond.
+func MaxPerMillisecond() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), 80)
+}
+
+// CountInTimeRange returns the number of ULIDs whose time components are in [startMs, endMs],
+// that is, the number of milliseconds in the range times 2^80.
+// It returns zero if startMs > endMs.
+func CountInTimeRange(startMs, endMs int64) *big.Int {
+	if startMs > endMs {
+		return new(big.Int)
+	}
+	n := big.NewInt(endMs)
+	n.Sub(n, big.NewInt(startMs))
+	n.Add(n, big.NewInt(1))
+	return n.Lsh(n, 80)
+}
+
+// Progress returns the position of id within [lo, hi] as a fraction between 0 and 1,
+// treating the ULIDs as 128-bit big-endian integers, for showing the progress of a scan.
+// The result is clamped to 0 if id is before lo and to 1 if id is after hi.
+// If lo equals hi, it returns 0 if id is before lo, and 1 otherwise.
+// It panics if lo is greater than hi.
+func Progress(id, lo, hi ULID) float64 {
+	if lo.Compare(hi) > 0 {
+		panic("ulid: lo must not be greater than hi")
+	}
+	if id.Compare(lo) < 0 {
+		return 0
+	}
+	if id.Compare(hi) >= 0 {
+		return 1
+	}
+	l := new(big.Int).SetBytes(lo[:])
+	pos := new(big.Int).SetBytes(id[:])
+	pos.Sub(pos, l)
+	width := new(big.Int).SetBytes(hi[:])
+	width.Sub(width, l)
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(pos), new(big.Float).SetInt(width)).Float64()
+	return f
+}
+
+// Partition splits [lo, hi) into n contiguous, non-overlapping sub-ranges [start, end) of nearly equal widths,
+// treating the ULIDs as 128-bit big-endian integers, for dividing a scan among n workers.
+// The first sub-range starts at lo, and the last one ends at hi.
+// If n is greater than the number of ULIDs in the range, it is reduced so that no sub-range is empty.
+// It returns nil if n <= 0 or lo >= hi.
+func Partition(lo, hi ULID, n int) [][2]ULID {
+	if n <= 0 || lo.Compare(hi) >= 0 {
+		return nil
+	}
+
+	l := new(big.Int).SetBytes(lo[:])
+	width := new(big.Int).SetBytes(hi[:])
+	width.Sub(width, l)
+	if width.IsInt64() && width.Int64() < int64(n) {
+		n = int(width.Int64())
+	}
+
+	bound := func(i int) ULID {
+		if i == n {
+			return hi
+		}
+		// lo + width * i / n
+		b := new(big.Int).Mul(width, big.NewInt(int64(i)))
+		b.Quo(b, big.NewInt(int64(n)))
+		b.Add(b, l)
+		var id ULID
+		b.FillBytes(id[:])
+		return id
+	}
+
+	parts := make([][2]ULID, n)
+	start := lo
+	for i := range n {
+		end := bound(i + 1)
+		parts[i] = [2]ULID{start, end}
+		start = end
+	}
+	return parts
+}
+
+// Since returns the time elapsed from a to b, computed from their time components.
+// The result is b.Time() - a.Time() milliseconds, so it is negative if b was generated before a.
+func Since(a, b ULID) time.Duration {
+	return time.Duration(b.Time()-a.Time()) * time.Millisecond
+}
+
+// WithinMillis reports whether the time components of id and other differ by at most tol milliseconds.
+// The random components are ignored. If tol is 0, it reports whether both are in the same millisecond.
+func (id ULID) WithinMillis(other ULID, tol int64) bool {
+	d := id.Time() - other.Time()
+	if d < 0 {
+		d = -d
+	}
+	return d <= tol
+}
+
+// SameBucket reports whether the time components of id and other fall into the same d-sized bucket,
+// where buckets are aligned to the Unix epoch, such as hours or days in UTC.
+// The random components are ignored.
+// If d is less than a millisecond, it reports whether both are in the same millisecond.
+func (id ULID) SameBucket(other ULID, d time.Duration) bool {
+	step := d.Milliseconds()
+	if step <= 0 {
+		return id.Time() == other.Time()
+	}
+	return id.Time()/step == other.Time()/step
+}
+
+// IncEntropy returns a copy of id with the random component incremented by one.
+// The time component is kept unchanged.
+// If the random component would overflow into the time component, it returns id and false.
+func (id ULID) IncEntropy() (ULID, bool) {
+	hi := binary.BigEndian.Uint16(id[6:])
+	lo := binary.BigEndian.Uint64(id[8:])
+	lo++
+	if lo == 0 {
+		hi++
+		if hi == 0 {
+			return id, false
+		}
+	}
+	binary.BigEndian.PutUint16(id[6:], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, true
+}
+
+// Next returns the ULID immediately following id, treating id as a 128-bit big-endian integer.
+// Unlike [ULID.IncEntropy], the increment carries into the time component.
+// The largest ULID wraps around to [Zero].
+func (id ULID) Next() ULID {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	lo++
+	if lo == 0 {
+		hi++
+	}
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id
+}
+
+// Prev returns the ULID immediately preceding id, treating id as a 128-bit big-endian integer.
+// The borrow propagates into the time component.
+// [Zero] wraps around to the largest ULID.
+func (id ULID) Prev() ULID {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	if lo == 0 {
+		hi--
+	}
+	lo--
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id
+}
+
+// Before returns the ULID that sorts immediately before ref, for use as an exclusive lower bound in range scans.
+// It is equivalent to ref.Prev(), except that it returns [Zero] if ref is Zero instead of wrapping around.
+func Before(ref ULID) ULID {
+	if ref.IsZero() {
+		return Zero
+	}
+	return ref.Prev()
+}
+
+// ImmediatelyPrecedes reports whether next is the ULID immediately following id, that is, next == id.Next().
+// It helps to verify that a sequence was generated by a strict monotonic generator without gaps.
+// The largest ULID does not precede any ULID.
+func (id ULID) ImmediatelyPrecedes(next ULID) bool {
+	return !next.IsZero() && id.Next() == next
+}
+
 // MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+// It allocates a new slice on each call; use [ULID.AppendBinary] to reuse a buffer.
 func (id ULID) MarshalBinary() ([]byte, error) {
 	ret := make([]byte, len(id))
 	copy(ret, id[:])
@@ -142,22 +728,378 @@ func (id *ULID) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// ReadFrom reads exactly one ULID, [BinarySize] bytes of the binary encoding, from r.
+// Any bytes after the ULID are left unread, so it can be called repeatedly to read a stream of ULIDs.
+// It returns [io.EOF] at a clean end of stream, that is, if no bytes are read,
+// and [ErrInvalidSize] if r ends in the middle of the ULID.
+// On error, id is unchanged.
+//
+// Although its signature matches, ReadFrom does not follow the contract of [io.ReaderFrom],
+// which reads until EOF and does not report EOF as an error, so do not pass a *ULID to [io.Copy].
+func (id *ULID) ReadFrom(r io.Reader) (int64, error) {
+	var buf [BinarySize]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err == io.ErrUnexpectedEOF {
+		return int64(n), ErrInvalidSize
+	}
+	if err != nil {
+		return int64(n), err
+	}
+	*id = buf
+	return int64(n), nil
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+// It writes the binary encoding of id to w.
+func (id ULID) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(id[:])
+	return int64(n), err
+}
+
+// MarshalBinaryLE returns the 16 bytes of id in reverse order,
+// for interoperating with systems that read the ULID as a little-endian 128-bit integer.
+// It is not the canonical binary encoding, which is big-endian (network byte order); see [ULID.MarshalBinary].
+func (id ULID) MarshalBinaryLE() ([]byte, error) {
+	ret := make([]byte, len(id))
+	for i, b := range id {
+		ret[len(id)-1-i] = b
+	}
+	return ret, nil
+}
+
+// UnmarshalBinaryLE decodes data encoded by [ULID.MarshalBinaryLE].
+func (id *ULID) UnmarshalBinaryLE(data []byte) error {
+	if len(data) != len(id) {
+		return ErrInvalidSize
+	}
+	for i, b := range data {
+		id[len(id)-1-i] = b
+	}
+	return nil
+}
+
+// UnmarshalBinaryStrict is like UnmarshalBinary, but it returns [ErrZero] if data is all zero bytes.
+// It is useful for schemas where the zero ULID means "unset".
+func (id *ULID) UnmarshalBinaryStrict(data []byte) error {
+	var tmp ULID
+	if err := tmp.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if tmp.IsZero() {
+		return ErrZero
+	}
+	*id = tmp
+	return nil
+}
+
 // AppendBinary implements the [encoding.BinaryAppender] interface.
+// Unlike MarshalBinary, it does not allocate if b has enough spare capacity,
+// so a buffer can be reused across calls by passing b[:0].
 func (id ULID) AppendBinary(b []byte) ([]byte, error) {
 	return append(b, id[:]...), nil
 }
 
+// MarshalBinaryTo appends the binary encoding of id to dst and returns the extended buffer.
+// It never allocates if dst has at least [BinarySize] bytes of spare capacity.
+func (id ULID) MarshalBinaryTo(dst []byte) []byte {
+	return append(dst, id[:]...)
+}
+
 // Parse parses a ULID from a string.
+// It does not allocate.
+// The length of s is checked before any character is read,
+// so an input of the wrong length, however long, is rejected with [ErrInvalidSize] in constant time.
 func Parse(s string) (ULID, error) {
 	return parse(s)
 }
 
+// IsCanonical reports whether s is exactly the canonical text encoding of a ULID,
+// that is, the upper case encoding returned by [ULID.String].
+// Strings that [Parse] accepts but are not canonical, that is, lower case or mixed case, are rejected.
+func IsCanonical(s string) bool {
+	id, err := parse(s)
+	if err != nil {
+		return false
+	}
+	buf := id.text()
+	return string(buf[:]) == s
+}
+
+// ParseClaim parses a ULID from a JWT claim value encoded by [ULID.ClaimString],
+// typically taken from claims decoded into a map[string]any.
+// It returns an error if v is not a string or is not the canonical text encoding;
+// accepting only one form keeps a ULID from being presented as several distinct claim values.
+func ParseClaim(v any) (ULID, error) {
+	s, ok := v.(string)
+	if !ok {
+		return ULID{}, fmt.Errorf("ulid: claim must be a string, got %T", v)
+	}
+	id, err := parse(s)
+	if err != nil {
+		return ULID{}, err
+	}
+	if buf := id.text(); string(buf[:]) != s {
+		return ULID{}, fmt.Errorf("ulid: claim %q is not canonical", s)
+	}
+	return id, nil
+}
+
+// ParseGrouped parses a ULID whose text encoding is split by hyphens for readability,
+// such as "01ARZ3ND-EKTSV4RR-FFQ69G5F-AV" returned by [ULID.StringGrouped].
+// All hyphens are removed before decoding, regardless of their positions.
+// It returns [ErrInvalidSize] if the rest is not [EncodedSize] characters.
+func ParseGrouped(s string) (ULID, error) {
+	var buf [EncodedSize]byte
+	n := 0
+	for i := range len(s) {
+		if s[i] == '-' {
+			continue
+		}
+		if n == len(buf) {
+			return ULID{}, ErrInvalidSize
+		}
+		buf[n] = s[i]
+		n++
+	}
+	return parse(buf[:n])
+}
+
+// NormalizeString returns the canonical upper case form of the text encoding s.
+// Lower case letters are converted to upper case, and the Crockford's Base32 aliases
+// I and L are replaced with 1 and O with 0.
+// It validates only the length and the characters of s; unlike parsing with [Parse]
+// and encoding with [ULID.String], it does not check that the time component fits in 48 bits,
+// because it only reformats s.
+// If s is already canonical, it is returned as is without allocation.
+func NormalizeString(s string) (string, error) {
+	if len(s) != EncodedSize {
+		if len(s) == 0 {
+			return "", ErrEmpty
+		}
+		return "", ErrInvalidSize
+	}
+	var buf [EncodedSize]byte
+	changed := false
+	for i := range len(s) {
+		c := norm[s[i]]
+		if c == 0 {
+			return "", ErrInvalidCharacter
+		}
+		buf[i] = c
+		changed = changed || c != s[i]
+	}
+	if !changed {
+		return s, nil
+	}
+	return string(buf[:]), nil
+}
+
+// norm maps the characters accepted by [NormalizeString] to the canonical characters, and others to zero.
+var norm = func() [256]byte {
+	var t [256]byte
+	for i, v := range dec {
+		if v >= 0 {
+			t[i] = enc[v]
+		}
+	}
+	t['I'], t['i'], t['L'], t['l'] = '1', '1', '1', '1'
+	t['O'], t['o'] = '0', '0'
+	return t
+}()
+
+// ParseGroupedHex parses a ULID encoded by [ULID.GroupedHex].
+// The hex digits are case-insensitive.
+func ParseGroupedHex(s string) (ULID, error) {
+	if len(s) != 3*BinarySize-1 {
+		return ULID{}, ErrInvalidSize
+	}
+	var id ULID
+	for i := range id {
+		if i > 0 && s[3*i-1] != ':' {
+			return ULID{}, ErrInvalidCharacter
+		}
+		if _, err := hex.Decode(id[i:i+1], []byte(s[3*i:3*i+2])); err != nil {
+			return ULID{}, ErrInvalidCharacter
+		}
+	}
+	return id, nil
+}
+
+// CommonPrefixLen returns the number of leading characters shared by a and b,
+// for choosing split points in a prefix index over ULID strings.
+// It compares the raw bytes and does not validate the inputs,
+// so a and b should be canonical text encodings; otherwise, for example,
+// the lower and upper case encodings of the same ULID share no prefix.
+func CommonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// ParseBytes parses a ULID from the text encoding in b.
+// It is like [Parse] but takes a byte slice, and it does not allocate.
+// It returns [ErrInvalidSize] if b is not exactly [EncodedSize] bytes.
+func ParseBytes(b []byte) (ULID, error) {
+	return parse(b)
+}
+
+// ParseBytesN parses a ULID from the first [EncodedSize] bytes of b and ignores the rest,
+// for fixed-offset wire formats.
+// It returns [ErrInvalidSize] if b is shorter than EncodedSize.
+// Use [DecodePrefix] to get the rest of b as well.
+func ParseBytesN(b []byte) (ULID, error) {
+	if len(b) > EncodedSize {
+		b = b[:EncodedSize]
+	}
+	return parse(b)
+}
+
+// ParseAfter is like Parse, but it returns [ErrTooOld] if the time component of the ULID is less than minMs in Unix milliseconds.
+// It is useful for rejecting replays of ancient ULIDs.
+func ParseAfter(s string, minMs int64) (ULID, error) {
+	id, err := parse(s)
+	if err != nil {
+		return ULID{}, err
+	}
+	if id.Time() < minMs {
+		return ULID{}, ErrTooOld
+	}
+	return id, nil
+}
+
+// ParseLine is like Parse, but it ignores a single trailing line terminator, "\n" or "\r\n".
+// A lone "\r" is not a line terminator and is rejected.
+// It is useful for parsing lines read by [bufio.Reader.ReadString].
+func ParseLine(s string) (ULID, error) {
+	if line, ok := strings.CutSuffix(s, "\n"); ok {
+		s = strings.TrimSuffix(line, "\r")
+	}
+	return parse(s)
+}
+
+// FromEnv parses a ULID from the environment variable named by key.
+// Leading and trailing white space is ignored.
+// It returns an error if the variable is not set or is not a valid ULID.
+func FromEnv(key string) (ULID, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return ULID{}, fmt.Errorf("ulid: environment variable %s is not set", key)
+	}
+	id, err := parse(strings.TrimSpace(v))
+	if err != nil {
+		return ULID{}, fmt.Errorf("ulid: environment variable %s: %w", key, err)
+	}
+	return id, nil
+}
+
+// ParseAuto parses a ULID from data, which may be either the binary or the text encoding.
+// The encoding is chosen purely by the length of data:
+// [BinarySize] bytes are decoded as binary, [EncodedSize] bytes as text,
+// and any other length results in [ErrInvalidSize].
+func ParseAuto(data []byte) (ULID, error) {
+	switch len(data) {
+	case BinarySize:
+		return ULID(data), nil
+	case EncodedSize:
+		return parse(data)
+	}
+	return ULID{}, ErrInvalidSize
+}
+
+// ParseWithChecksum parses a ULID with a checksum character encoded by [ULID.StringWithChecksum].
+// It returns [ErrChecksum] if the checksum character does not match.
+func ParseWithChecksum(s string) (ULID, error) {
+	if len(s) != EncodedSize+1 {
+		return ULID{}, ErrInvalidSize
+	}
+	id, err := parse(s[:EncodedSize])
+	if err != nil {
+		return ULID{}, err
+	}
+	c := dec[s[EncodedSize]]
+	if c < 0 {
+		return ULID{}, ErrInvalidCharacter
+	}
+	if c != checksum(s[:EncodedSize]) {
+		return ULID{}, ErrChecksum
+	}
+	return id, nil
+}
+
+// ParseBase36 parses a ULID encoded by [ULID.Base36].
+// It is case-insensitive.
+func ParseBase36(s string) (ULID, error) {
+	if len(s) != Base36Size {
+		return ULID{}, ErrInvalidSize
+	}
+	for i := range len(s) {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			return ULID{}, ErrInvalidCharacter
+		}
+	}
+	n, ok := new(big.Int).SetString(s, 36)
+	if !ok {
+		return ULID{}, ErrInvalidCharacter
+	}
+	if n.BitLen() > 128 {
+		return ULID{}, ErrOverflow
+	}
+	var id ULID
+	n.FillBytes(id[:])
+	return id, nil
+}
+
+// checksum returns the sum of the values of the characters in s modulo 32.
+// All characters in s must be valid.
+func checksum[T bs](s T) int8 {
+	var sum int8
+	for i := range len(s) {
+		sum += dec[s[i]]
+	}
+	return sum & 0x1f
+}
+
+// FindAll returns all ULIDs embedded in s, in the order they appear.
+// A ULID is reported only if it is a whole token: a run of exactly 26 characters of the alphabet
+// that is preceded and followed by a character outside the alphabet or by the boundary of s.
+// Longer runs, such as hex trace IDs, are skipped entirely rather than split into bogus ULIDs.
+func FindAll(s string) []ULID {
+	var ids []ULID
+	for i := 0; i < len(s); {
+		if dec[s[i]] < 0 {
+			i++
+			continue
+		}
+		// find the end of the run of alphabet characters.
+		j := i + 1
+		for j < len(s) && dec[s[j]] >= 0 {
+			j++
+		}
+		if j-i == EncodedSize {
+			if id, err := parse(s[i:j]); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		i = j
+	}
+	return ids
+}
+
 type bs interface {
 	[]byte | string
 }
 
 func parse[T bs](s T) (ULID, error) {
+	// Check the length first; callers rely on rejecting long inputs without scanning them.
 	if len(s) != EncodedSize {
+		if len(s) == 0 {
+			return ULID{}, ErrEmpty
+		}
 		return ULID{}, ErrInvalidSize
 	}
 
@@ -275,7 +1217,30 @@ var dec = [...]int8{
 
 const enc = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
 
+// Alphabet returns the 32 characters of the Crockford's Base32 alphabet used by the canonical text encoding,
+// in the order of their values.
+func Alphabet() string {
+	return enc
+}
+
+// DecodeTable returns a copy of the table used to decode the text encoding.
+// It maps each byte to its value between 0 and 31, or to -1 if the byte is not a valid character.
+// Both upper and lower case letters are valid.
+func DecodeTable() [256]int8 {
+	return dec
+}
+
+// alphabets used by text.
+var (
+	encUpper = [32]byte([]byte(enc))
+	encLower = [32]byte([]byte("0123456789abcdefghjkmnpqrstvwxyz"))
+)
+
 func (id ULID) text() [26]byte {
+	return id.textWith(&encUpper)
+}
+
+func (id ULID) textWith(alphabet *[32]byte) [26]byte {
 	var buf [26]byte
 
 	// Optimized unrolled loop ahead.
@@ -287,44 +1252,130 @@ func (id ULID) text() [26]byte {
 		uint64(id[14])<<8 | uint64(id[15])
 
 	// 10 bytes timestamp
-	buf[0] = enc[(h>>61)&0x1f]
-	buf[1] = enc[(h>>56)&0x1f]
-	buf[2] = enc[(h>>51)&0x1f]
-	buf[3] = enc[(h>>46)&0x1f]
-	buf[4] = enc[(h>>41)&0x1f]
-	buf[5] = enc[(h>>36)&0x1f]
-	buf[6] = enc[(h>>31)&0x1f]
-	buf[7] = enc[(h>>26)&0x1f]
-	buf[8] = enc[(h>>21)&0x1f]
-	buf[9] = enc[(h>>16)&0x1f]
+	buf[0] = alphabet[(h>>61)&0x1f]
+	buf[1] = alphabet[(h>>56)&0x1f]
+	buf[2] = alphabet[(h>>51)&0x1f]
+	buf[3] = alphabet[(h>>46)&0x1f]
+	buf[4] = alphabet[(h>>41)&0x1f]
+	buf[5] = alphabet[(h>>36)&0x1f]
+	buf[6] = alphabet[(h>>31)&0x1f]
+	buf[7] = alphabet[(h>>26)&0x1f]
+	buf[8] = alphabet[(h>>21)&0x1f]
+	buf[9] = alphabet[(h>>16)&0x1f]
 
 	// 16 bytes random
-	buf[10] = enc[(h>>11)&0x1f]
-	buf[11] = enc[(h>>6)&0x1f]
-	buf[12] = enc[(h>>1)&0x1f]
-	buf[13] = enc[(h<<4|l>>60)&0x1f]
-	buf[14] = enc[(l>>55)&0x1f]
-	buf[15] = enc[(l>>50)&0x1f]
-	buf[16] = enc[(l>>45)&0x1f]
-	buf[17] = enc[(l>>40)&0x1f]
-	buf[18] = enc[(l>>35)&0x1f]
-	buf[19] = enc[(l>>30)&0x1f]
-	buf[20] = enc[(l>>25)&0x1f]
-	buf[21] = enc[(l>>20)&0x1f]
-	buf[22] = enc[(l>>15)&0x1f]
-	buf[23] = enc[(l>>10)&0x1f]
-	buf[24] = enc[(l>>5)&0x1f]
-	buf[25] = enc[l&0x1f]
+	buf[10] = alphabet[(h>>11)&0x1f]
+	buf[11] = alphabet[(h>>6)&0x1f]
+	buf[12] = alphabet[(h>>1)&0x1f]
+	buf[13] = alphabet[(h<<4|l>>60)&0x1f]
+	buf[14] = alphabet[(l>>55)&0x1f]
+	buf[15] = alphabet[(l>>50)&0x1f]
+	buf[16] = alphabet[(l>>45)&0x1f]
+	buf[17] = alphabet[(l>>40)&0x1f]
+	buf[18] = alphabet[(l>>35)&0x1f]
+	buf[19] = alphabet[(l>>30)&0x1f]
+	buf[20] = alphabet[(l>>25)&0x1f]
+	buf[21] = alphabet[(l>>20)&0x1f]
+	buf[22] = alphabet[(l>>15)&0x1f]
+	buf[23] = alphabet[(l>>10)&0x1f]
+	buf[24] = alphabet[(l>>5)&0x1f]
+	buf[25] = alphabet[l&0x1f]
 
 	return buf
 }
 
+// String returns the canonical text encoding of id.
+// It allocates the returned string; use [ULID.AppendText] to encode into a reusable buffer without allocation.
 func (id ULID) String() string {
 	buf := id.text()
 	return string(buf[:])
 }
 
+// StringWithChecksum returns the canonical text encoding of id followed by a checksum character, 27 characters in total.
+// The checksum character encodes the sum of the values of the 26 characters modulo 32,
+// which detects any single-character corruption.
+// Use [ParseWithChecksum] to parse it.
+func (id ULID) StringWithChecksum() string {
+	var buf [EncodedSize + 1]byte
+	text := id.text()
+	copy(buf[:], text[:])
+	buf[EncodedSize] = enc[checksum(text[:])]
+	return string(buf[:])
+}
+
+// ClaimString returns the canonical text encoding of id for use as a JWT claim value, such as "jti".
+// It is always upper case, so claims compare and sort consistently;
+// because ULIDs sort lexicographically by time, tokens can be ordered by the claim alone.
+// Use [ParseClaim] to parse it.
+func (id ULID) ClaimString() string {
+	return id.String()
+}
+
+// StringGrouped returns the canonical text encoding of id split into groups of 8 characters by hyphens,
+// such as "01ARZ3ND-EKTSV4RR-FFQ69G5F-AV", for printed forms.
+// Use [ParseGrouped] to parse it.
+func (id ULID) StringGrouped() string {
+	var buf [EncodedSize + 3]byte
+	text := id.text()
+	copy(buf[0:8], text[0:8])
+	buf[8] = '-'
+	copy(buf[9:17], text[8:16])
+	buf[17] = '-'
+	copy(buf[18:26], text[16:24])
+	buf[26] = '-'
+	copy(buf[27:29], text[24:26])
+	return string(buf[:])
+}
+
+// Hex returns the 16 bytes of id as 32 lower case hex characters, for debugging dumps.
+// Use [ULID.AppendHex] to avoid the allocation.
+func (id ULID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+// AppendHex appends the 16 bytes of id as 32 lower case hex characters to dst and returns the extended buffer.
+// It produces the same output as [ULID.Hex], and it does not allocate if dst has enough spare capacity.
+func (id ULID) AppendHex(dst []byte) []byte {
+	return hex.AppendEncode(dst, id[:])
+}
+
+// GroupedHex returns the 16 bytes of id as colon-separated lower case hex pairs,
+// such as "01:56:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5b", like the display of a MAC address.
+// It is meant for visually comparing binary ULIDs.
+// Use [ParseGroupedHex] to parse it.
+func (id ULID) GroupedHex() string {
+	var buf [3*BinarySize - 1]byte
+	for i, b := range id {
+		if i > 0 {
+			buf[3*i-1] = ':'
+		}
+		hex.Encode(buf[3*i:3*i+2], []byte{b})
+	}
+	return string(buf[:])
+}
+
+// PathSegment returns the lower case text encoding of id for use in URL paths.
+// It is equivalent to [ULID.AppendTextCase] with upper set to false;
+// the Crockford's Base32 alphabet contains only characters safe in a URL path segment.
+// The result can be parsed by [Parse], which is case-insensitive.
+func (id ULID) PathSegment() string {
+	buf := id.textWith(&encLower)
+	return string(buf[:])
+}
+
+// Base36Size is the size of a ULID when encoded by [ULID.Base36].
+const Base36Size = 25
+
+// Base36 returns the Base36 encoding of id for interoperability with legacy systems.
+// The result is 25 characters of 0-9 and A-Z, padded with leading zeros.
+// It is not the canonical encoding; use [ULID.String] unless Base36 is required.
+func (id ULID) Base36() string {
+	s := strings.ToUpper(new(big.Int).SetBytes(id[:]).Text(36))
+	return strings.Repeat("0", Base36Size-len(s)) + s
+}
+
 // MarshalText implements the [encoding.TextMarshaler] interface.
+// It allocates a new slice on each call; use [ULID.AppendText] to reuse a buffer.
 func (id ULID) MarshalText() ([]byte, error) {
 	buf := id.text()
 	return buf[:], nil
@@ -341,9 +1392,162 @@ func (id *ULID) UnmarshalText(data []byte) error {
 }
 
 // AppendText implements the [encoding.TextAppender] interface.
+// If b has at least [EncodedSize] bytes of spare capacity, the text is appended in place
+// and the returned slice shares the backing array of b.
 func (id ULID) AppendText(b []byte) ([]byte, error) {
+	return id.AppendTextCase(b, true), nil
+}
+
+// AppendTextCase appends the text encoding of id to b and returns the extended buffer.
+// If upper is true, it uses the canonical upper case alphabet, otherwise the lower case one.
+func (id ULID) AppendTextCase(b []byte, upper bool) []byte {
+	alphabet := &encLower
+	if upper {
+		alphabet = &encUpper
+	}
+	buf := id.textWith(alphabet)
+	return append(b, buf[:]...)
+}
+
+// AppendJSON appends the JSON encoding of id, the quoted text encoding, to b and returns the extended buffer.
+// It produces the same output as encoding/json without reflection,
+// and it does not allocate if b has enough spare capacity.
+func (id ULID) AppendJSON(b []byte) []byte {
+	b = append(b, '"')
+	b = id.AppendTextCase(b, true)
+	return append(b, '"')
+}
+
+// AppendTo appends the canonical text encoding of id to dst.
+// It returns the number of bytes appended, which is always [EncodedSize], and the extended slice.
+func (id ULID) AppendTo(dst []byte) (n int, out []byte) {
 	buf := id.text()
-	return append(b, buf[:]...), nil
+	return len(buf), append(dst, buf[:]...)
+}
+
+// AppendBinaryMany appends the binary encodings of ids to dst back to back, [BinarySize]*len(ids) bytes in total,
+// and returns the extended buffer.
+func AppendBinaryMany(dst []byte, ids []ULID) []byte {
+	dst = slices.Grow(dst, BinarySize*len(ids))
+	for _, id := range ids {
+		dst = append(dst, id[:]...)
+	}
+	return dst
+}
+
+// ParseBinaryMany decodes ULIDs encoded by [AppendBinaryMany].
+// It returns [ErrInvalidSize] if the length of b is not a multiple of [BinarySize].
+func ParseBinaryMany(b []byte) ([]ULID, error) {
+	if len(b)%BinarySize != 0 {
+		return nil, ErrInvalidSize
+	}
+	ids := make([]ULID, len(b)/BinarySize)
+	for i := range ids {
+		ids[i] = ULID(b[i*BinarySize:])
+	}
+	return ids, nil
+}
+
+// Concat returns the binary encodings of ids concatenated, for building composite keys such as a tenant ULID followed by a resource ULID.
+// The concatenation preserves the ordering of the ULIDs column by column.
+func Concat(ids ...ULID) []byte {
+	return AppendBinaryMany(nil, ids)
+}
+
+// SplitConcat splits b encoded by [Concat] into the ULIDs.
+// It returns [ErrInvalidSize] if the length of b is not a multiple of [BinarySize].
+func SplitConcat(b []byte) ([]ULID, error) {
+	return ParseBinaryMany(b)
+}
+
+// AppendLengthPrefixed appends a single byte holding [EncodedSize] followed by the text encoding of id to b.
+// It is the inverse of [ParseLengthPrefixed].
+func (id ULID) AppendLengthPrefixed(b []byte) []byte {
+	b = append(b, EncodedSize)
+	return id.AppendTextCase(b, true)
+}
+
+// AppendBinaryLengthPrefixed appends a single byte holding [BinarySize] followed by the binary encoding of id to b.
+// It is the inverse of [ParseBinaryLengthPrefixed].
+func (id ULID) AppendBinaryLengthPrefixed(b []byte) []byte {
+	b = append(b, BinarySize)
+	return append(b, id[:]...)
+}
+
+// ParseLengthPrefixed parses a ULID encoded by [ULID.AppendLengthPrefixed] from the beginning of b.
+// It returns the ULID and the rest of b.
+func ParseLengthPrefixed(b []byte) (ULID, []byte, error) {
+	if len(b) < 1+EncodedSize || b[0] != EncodedSize {
+		return ULID{}, b, ErrInvalidSize
+	}
+	id, err := parse(b[1 : 1+EncodedSize])
+	if err != nil {
+		return ULID{}, b, err
+	}
+	return id, b[1+EncodedSize:], nil
+}
+
+// ParseBinaryLengthPrefixed parses a ULID encoded by [ULID.AppendBinaryLengthPrefixed] from the beginning of b.
+// It returns the ULID and the rest of b.
+func ParseBinaryLengthPrefixed(b []byte) (ULID, []byte, error) {
+	if len(b) < 1+BinarySize || b[0] != BinarySize {
+		return ULID{}, b, ErrInvalidSize
+	}
+	return ULID(b[1 : 1+BinarySize]), b[1+BinarySize:], nil
+}
+
+// DecodePrefix parses a ULID from the first [EncodedSize] bytes of b as text.
+// It returns the ULID and the rest of b.
+func DecodePrefix(b []byte) (ULID, []byte, error) {
+	if len(b) < EncodedSize {
+		return ULID{}, b, ErrInvalidSize
+	}
+	id, err := parse(b[:EncodedSize])
+	if err != nil {
+		return ULID{}, b, err
+	}
+	return id, b[EncodedSize:], nil
+}
+
+// DecodeBinaryPrefix parses a ULID from the first [BinarySize] bytes of b as binary.
+// It returns the ULID and the rest of b.
+func DecodeBinaryPrefix(b []byte) (ULID, []byte, error) {
+	if len(b) < BinarySize {
+		return ULID{}, b, ErrInvalidSize
+	}
+	return ULID(b[:BinarySize]), b[BinarySize:], nil
+}
+
+// SortKey64 returns the top 64 bits of id in big-endian order: the time component and the high 16 bits of the random component.
+// Ordering by SortKey64 agrees with [ULID.Compare] for ULIDs with distinct keys,
+// but different ULIDs may share the same key, especially within the same millisecond.
+// Use Compare for the full 128-bit ordering.
+func (id ULID) SortKey64() uint64 {
+	return binary.BigEndian.Uint64(id[:8])
+}
+
+// Fingerprint returns a deterministic RGB color derived from the random component of id,
+// for rendering a visual fingerprint of the ULID in dashboards.
+// The 10 random bytes are folded into three bytes by XOR; the time component is not used.
+func (id ULID) Fingerprint() (r, g, b uint8) {
+	r = id[6] ^ id[9] ^ id[12] ^ id[15]
+	g = id[7] ^ id[10] ^ id[13]
+	b = id[8] ^ id[11] ^ id[14]
+	return
+}
+
+// Key returns the 16 bytes of id as an array.
+// It is useful for embedding the ULID in a larger fixed-size key.
+func (id ULID) Key() [16]byte {
+	return id
+}
+
+// Wipe overwrites id with zeros so that a sensitive ULID does not linger in memory after use.
+// It is best-effort: copies of the ULID made before, such as by passing it by value, are not wiped.
+func (id *ULID) Wipe() {
+	clear(id[:])
+	// keep the store from being eliminated as dead.
+	runtime.KeepAlive(id)
 }
 
 // IsZero returns true if the ULID is the zero value.
@@ -351,12 +1555,140 @@ func (id ULID) IsZero() bool {
 	return id == Zero
 }
 
+// IsStrictlySorted reports whether each element of ids is strictly greater than the previous one by [ULID.Compare],
+// that is, ids is sorted in ascending order and has no duplicates.
+// It is useful for asserting invariants of generated ULIDs.
+func IsStrictlySorted(ids []ULID) bool {
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1].Compare(ids[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BinarySearch searches for target in sorted, which must be sorted in ascending order by [ULID.Compare].
+// It returns the position where target is found, or the position where target would be inserted,
+// and reports whether target is found.
+func BinarySearch(sorted []ULID, target ULID) (int, bool) {
+	return slices.BinarySearchFunc(sorted, target, ULID.Compare)
+}
+
+// FindDuplicates returns the ULIDs that appear more than once in ids, each reported once, in ascending order.
+// It sorts a copy of ids and scans adjacent elements, so ids is not modified.
+// It returns nil if there are no duplicates.
+func FindDuplicates(ids []ULID) []ULID {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, ULID.Compare)
+
+	var dups []ULID
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] != sorted[i-1] {
+			continue
+		}
+		if len(dups) > 0 && dups[len(dups)-1] == sorted[i] {
+			continue
+		}
+		dups = append(dups, sorted[i])
+	}
+	return dups
+}
+
+// IsSuspicious reports whether id looks degenerate: the zero ULID, a ULID whose random component is all one bits,
+// or a ULID whose 16 bytes are all the same.
+// Such ULIDs are valid but often indicate a bug upstream, such as an uninitialized value or a broken random number generator.
+// It is advisory only.
+func IsSuspicious(id ULID) bool {
+	if id == MaxForTime(id.Time()) {
+		return true
+	}
+	for _, b := range id[1:] {
+		if b != id[0] {
+			return false
+		}
+	}
+	return true
+}
+
 // Compare returns an integer comparing two ULIDs lexicographically.
 // The result will be 0 if id==other, -1 if id < other, and +1 if id > other.
 func (id ULID) Compare(other ULID) int {
 	return bytes.Compare(id[:], other[:])
 }
 
+// EntropyHammingDistance returns the number of differing bits between the random components of id and other, from 0 to 80.
+// Consistently low distances across many pairs of ULIDs may indicate a weak random number generator.
+func (id ULID) EntropyHammingDistance(other ULID) int {
+	var n int
+	for i := 6; i < len(id); i++ {
+		n += bits.OnesCount8(id[i] ^ other[i])
+	}
+	return n
+}
+
+// XORDistance returns the byte-wise XOR of id and other, for Kademlia-style routing keyed on ULIDs.
+// The result is interpreted as a 128-bit big-endian distance and can be ordered by [ULID.Compare].
+// Its time component is not a meaningful time.
+func (id ULID) XORDistance(other ULID) ULID {
+	for i := range id {
+		id[i] ^= other[i]
+	}
+	return id
+}
+
+// CompareNullLast is like Compare, but it treats the zero ULID as greater than all other ULIDs.
+// Two zero ULIDs are equal.
+func (id ULID) CompareNullLast(other ULID) int {
+	switch {
+	case id.IsZero() && other.IsZero():
+		return 0
+	case id.IsZero():
+		return 1
+	case other.IsZero():
+		return -1
+	}
+	return id.Compare(other)
+}
+
+// Shard returns a shard number in [0, n) for id, for distributing ULIDs across n shards.
+// It is computed from the low 64 bits of the random component, so it is stable for id
+// and evenly distributed for ULIDs generated by [Make], regardless of their time components.
+// It panics if n is not positive.
+func (id ULID) Shard(n int) int {
+	if n <= 0 {
+		panic("ulid: the number of shards must be positive")
+	}
+	return int(binary.BigEndian.Uint64(id[8:]) % uint64(n))
+}
+
+// ShardKey returns [ULID.Shard] as a zero-padded decimal string for building paths such as "shard-007/...".
+// The width is the number of digits of n-1, so all keys for the same n have the same length and sort numerically.
+// It panics if n is not positive.
+func (id ULID) ShardKey(n int) string {
+	shard := id.Shard(n)
+	width := len(strconv.Itoa(n - 1))
+	return fmt.Sprintf("%0*d", width, shard)
+}
+
+// Tag returns the first byte of the random component of id (byte 6),
+// for callers that embed a marker byte to tag the origin of the ULID.
+func (id ULID) Tag() byte {
+	return id[6]
+}
+
+// WithTag returns a copy of id with the first byte of the random component (byte 6) set to b.
+// The tag consumes 8 bits of the random component, leaving 72 bits of entropy.
+func (id ULID) WithTag(b byte) ULID {
+	id[6] = b
+	return id
+}
+
+// SameEntropy reports whether id and other have the same random component, ignoring the time component.
+// Two ULIDs sharing the random component across different milliseconds may indicate a broken random number generator.
+func (id ULID) SameEntropy(other ULID) bool {
+	return bytes.Equal(id[6:], other[6:])
+}
+
 // Scan implements the [database/sql.Scanner] interface.
 func (id *ULID) Scan(src any) error {
 	switch x := src.(type) {
@@ -6,8 +6,16 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"io"
+	"math"
+	"net/url"
+	"os"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"testing/synctest"
 	"time"
@@ -73,6 +81,73 @@ func TestMake(t *testing.T) {
 	}
 }
 
+func TestMakeSecond(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		time.Sleep(1259 * time.Millisecond)
+		now := time.Now().UnixMilli()
+		id := MakeSecond()
+		if id.Time()%1000 != 0 {
+			t.Fatalf("time=%d", id.Time())
+		}
+		if id.Time() != now-259 {
+			t.Fatalf("want %d, got %d", now-259, id.Time())
+		}
+		if id2 := MakeSecond(); id2.Time() != id.Time() || id2 == id {
+			t.Fatalf("id=%v, id2=%v", id, id2)
+		}
+	})
+}
+
+func TestGenerateSpread(t *testing.T) {
+	start := time.UnixMilli(1469918176385)
+	end := start.Add(time.Second)
+
+	t.Run("spread", func(t *testing.T) {
+		ids := GenerateSpread(11, start, end)
+		if len(ids) != 11 {
+			t.Fatalf("len=%d", len(ids))
+		}
+		for i, id := range ids {
+			if want := start.UnixMilli() + int64(i)*100; id.Time() != want {
+				t.Fatalf("ids[%d].Time()=%d, want %d", i, id.Time(), want)
+			}
+		}
+	})
+
+	t.Run("sorted and within bounds", func(t *testing.T) {
+		ids := GenerateSpread(10000, start, end)
+		if len(ids) != 10000 {
+			t.Fatalf("len=%d", len(ids))
+		}
+		if ids[0].Time() != start.UnixMilli() || ids[len(ids)-1].Time() != end.UnixMilli() {
+			t.Fatalf("first=%d last=%d", ids[0].Time(), ids[len(ids)-1].Time())
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i-1].Compare(ids[i]) > 0 {
+				t.Fatalf("not sorted: ids[%d]=%v ids[%d]=%v", i-1, ids[i-1], i, ids[i])
+			}
+		}
+	})
+
+	t.Run("n == 0", func(t *testing.T) {
+		if ids := GenerateSpread(0, start, end); len(ids) != 0 {
+			t.Fatalf("len=%d", len(ids))
+		}
+	})
+
+	t.Run("start == end", func(t *testing.T) {
+		ids := GenerateSpread(3, start, start)
+		for _, id := range ids {
+			if id.Time() != start.UnixMilli() {
+				t.Fatalf("time=%d", id.Time())
+			}
+		}
+		if !slices.IsSortedFunc(ids, ULID.Compare) {
+			t.Fatalf("not sorted: %v", ids)
+		}
+	})
+}
+
 func TestMakeMonotonic(t *testing.T) {
 	// Test that MakeMonotonic() generates a ULID with the expected time and random components.
 	synctest.Test(t, func(t *testing.T) {
@@ -128,6 +203,7 @@ func TestMakeMonotonic(t *testing.T) {
 }
 
 func BenchmarkMake(b *testing.B) {
+	b.ReportAllocs()
 	for b.Loop() {
 		runtime.KeepAlive(Make())
 	}
@@ -155,259 +231,2747 @@ func BenchmarkMakeMonotonicParallel(b *testing.B) {
 	})
 }
 
-func TestSetTime(t *testing.T) {
-	t.Run("valid", func(t *testing.T) {
-		var id ULID
-		id.SetTime(0x1563e3ab5d3)
-		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
-			t.Fatalf("id=%x", [16]byte(id))
+func TestDerive(t *testing.T) {
+	ns := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id := Derive(ns, []byte("example.com"))
+	if id != (ULID{0x23, 0x37, 0x32, 0xc5, 0x3e, 0x57, 0x6e, 0x3f, 0xc3, 0xeb, 0x6a, 0xe3, 0xd0, 0xf6, 0xb7, 0x48}) {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+
+	// Test that the same inputs always yield the same output.
+	if id2 := Derive(ns, []byte("example.com")); id != id2 {
+		t.Fatalf("id=%v id2=%v", id, id2)
+	}
+
+	// Test that different inputs yield different outputs.
+	if id2 := Derive(ns, []byte("example.org")); id == id2 {
+		t.Fatalf("different names yield the same ULID: %v", id)
+	}
+	if id2 := Derive(Zero, []byte("example.com")); id == id2 {
+		t.Fatalf("different namespaces yield the same ULID: %v", id)
+	}
+}
+
+func TestMakeContentAddressed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		id1 := MakeContentAddressed([]byte("hello"))
+		if id1 != (ULID{0x00, 0xdc, 0x6a, 0xcf, 0xac, 0x00, 0x2c, 0xf2, 0x4d, 0xba, 0x5f, 0xb0, 0xa3, 0x0e, 0x26, 0xe8}) {
+			t.Fatalf("id=%x", [16]byte(id1))
 		}
-	})
 
-	t.Run("invalid negative", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Fatalf("did not panic")
-			}
-		}()
-		var id ULID
-		id.SetTime(-1)
-	})
+		// identical payloads in the same millisecond collide.
+		if id2 := MakeContentAddressed([]byte("hello")); id2 != id1 {
+			t.Fatalf("want %v, got %v", id1, id2)
+		}
 
-	t.Run("max valid", func(t *testing.T) {
-		var id ULID
-		id.SetTime(0xFFFFFFFFFFFF)
-		if id != (ULID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
-			t.Fatalf("id=%x", [16]byte(id))
+		// different payloads do not.
+		if id3 := MakeContentAddressed([]byte("world")); id3 == id1 || id3.Time() != id1.Time() {
+			t.Fatalf("id1=%v, id3=%v", id1, id3)
 		}
-	})
 
-	t.Run("invalid overflow", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Fatalf("did not panic")
-			}
-		}()
-		var id ULID
-		id.SetTime(0x1000000000000)
+		// identical payloads in different milliseconds share only the random component.
+		time.Sleep(time.Millisecond)
+		id4 := MakeContentAddressed([]byte("hello"))
+		if id4 == id1 || !id4.SameEntropy(id1) {
+			t.Fatalf("id1=%v, id4=%v", id1, id4)
+		}
 	})
 }
 
-func TestTime(t *testing.T) {
+func TestTimestamp(t *testing.T) {
 	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	if id.Time() != 0x1563e3ab5d3 {
-		t.Fatalf("time=%x", id.Time())
+	want := time.Date(2016, time.July, 30, 23, 54, 10, 259000000, time.UTC)
+	if got := id.Timestamp(); !got.Equal(want) {
+		t.Fatalf("want %v, got %v", want, got)
 	}
 }
 
-func TestMarshalBinary(t *testing.T) {
+func TestDate(t *testing.T) {
+	// 2016-07-30T23:54:10.259Z, which is 2016-07-31 in time zones east of UTC+1.
 	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	data, err := id.MarshalBinary()
-	if err != nil {
-		t.Fatal(err)
+	year, month, day := id.Date()
+	if year != 2016 || month != time.July || day != 30 {
+		t.Fatalf("date=%d-%d-%d", year, month, day)
 	}
-	want := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	if !bytes.Equal(data, want) {
-		t.Fatalf("data=%x", data)
+}
+
+func TestParts(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	ms, entropy := id.Parts()
+	if ms != 0x1563e3ab5d3 {
+		t.Fatalf("ms=%x", ms)
+	}
+	if entropy != [10]byte{0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b} {
+		t.Fatalf("entropy=%x", entropy)
+	}
+
+	// Test that the parts recombine into the original ULID.
+	var got ULID
+	got.SetTime(ms)
+	copy(got[6:], entropy[:])
+	if got != id {
+		t.Fatalf("want %v, got %v", id, got)
 	}
 }
 
-func TestUnmarshalBinary(t *testing.T) {
-	t.Run("valid", func(t *testing.T) {
-		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-		var id ULID
-		if err := id.UnmarshalBinary(data); err != nil {
-			t.Fatal(err)
-		}
-		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-		if id != want {
-			t.Fatalf("want %v, got %v", want, id)
-		}
-	})
+func TestTimeBytes(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	orig := id
 
-	t.Run("invalid size", func(t *testing.T) {
-		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd}
-		var id ULID
-		err := id.UnmarshalBinary(data)
-		if !errors.Is(err, ErrInvalidSize) {
-			t.Fatal(err)
-		}
-	})
+	tb := id.TimeBytes()
+	if !bytes.Equal(tb, []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3}) {
+		t.Fatalf("time bytes=%x", tb)
+	}
+	entropy := id.Entropy()
+	if !bytes.Equal(entropy, []byte{0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
+		t.Fatalf("entropy=%x", entropy)
+	}
+	if !bytes.Equal(append(tb, entropy...), id[:]) {
+		t.Fatal("the parts do not recombine into the original ULID")
+	}
 
+	// Test that the returned slices are copies.
+	tb[0] = 0xff
+	entropy[0] = 0xff
+	if id != orig {
+		t.Fatalf("id was modified: %v", id)
+	}
 }
 
-func TestAppendBinary(t *testing.T) {
+func TestPartitionPath(t *testing.T) {
+	// 2016-07-30T23:54:10.259Z
 	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	data, err := id.AppendBinary(nil)
-	if err != nil {
-		t.Fatal(err)
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"2006/01/02/15", "2016/07/30/23"},
+		{"year=2006/month=01/day=02", "year=2016/month=07/day=30"},
+		{"20060102", "20160730"},
 	}
-	want := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	if !bytes.Equal(data, want) {
-		t.Fatalf("data=%x", data)
+	for _, tt := range tests {
+		if got := id.PartitionPath(tt.layout); got != tt.want {
+			t.Errorf("PartitionPath(%q)=%q, want %q", tt.layout, got, tt.want)
+		}
 	}
 }
 
-func TestParse(t *testing.T) {
-	t.Run("valid ulid", func(t *testing.T) {
-		id, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV")
-		if err != nil {
-			t.Fatal(err)
-		}
-		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
-			t.Fatalf("id=%x", [16]byte(id))
-		}
-	})
+func TestULID_Before(t *testing.T) {
+	var id ULID
+	id.SetTime(1469918176385)
+	if !id.Before(time.UnixMilli(1469918176386)) {
+		t.Fatal("want true, got false")
+	}
+	if id.Before(time.UnixMilli(1469918176385)) {
+		t.Fatal("same millisecond: want false, got true")
+	}
+	if id.Before(time.UnixMilli(1469918176384)) {
+		t.Fatal("want false, got true")
+	}
+}
 
-	t.Run("lower case ulid", func(t *testing.T) {
-		id, err := Parse("01arz3ndektsv4rrffq69g5fav")
-		if err != nil {
-			t.Fatal(err)
+func TestOlderThan(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		id := Make()
+		time.Sleep(time.Minute)
+		if !id.OlderThan(59 * time.Second) {
+			t.Fatal("want true, got false")
 		}
-		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
-			t.Fatalf("id=%x", [16]byte(id))
+		if id.OlderThan(time.Minute) {
+			t.Fatal("want false, got true")
 		}
 	})
+}
 
-	t.Run("invalid size", func(t *testing.T) {
-		_, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FA")
-		if err != ErrInvalidSize {
-			t.Fatalf("err=%v", err)
+func TestPlausibleMake(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		now := time.Now().UnixMilli()
+		id := Make()
+		if !id.PlausibleMake(time.Minute) {
+			t.Fatal("fresh ULID: want true, got false")
 		}
-	})
 
-	t.Run("invalid character", func(t *testing.T) {
-		_, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FA!")
-		if err != ErrInvalidCharacter {
-			t.Fatalf("err=%v", err)
+		id.SetTime(now - time.Minute.Milliseconds())
+		if !id.PlausibleMake(time.Minute) {
+			t.Fatal("in window: want true, got false")
 		}
-	})
-
-	t.Run("overflow", func(t *testing.T) {
-		_, err := Parse("80000000000000000000000000")
-		if err != ErrOverflow {
-			t.Fatalf("err=%v", err)
+		id.SetTime(now - time.Minute.Milliseconds() - 1)
+		if id.PlausibleMake(time.Minute) {
+			t.Fatal("too old: want false, got true")
 		}
-	})
-}
-
-func BenchmarkParse(b *testing.B) {
-	const s = "0000XSNJG0MQJHBF4QX1EFD6Y3"
-	for b.Loop() {
-		id, err := Parse(s)
-		if err != nil {
-			b.Fatal(err)
+		id.SetTime(now + time.Minute.Milliseconds() + 1)
+		if id.PlausibleMake(time.Minute) {
+			t.Fatal("in the future: want false, got true")
 		}
-		runtime.KeepAlive(id)
-	}
-}
 
-func FuzzParse(f *testing.F) {
-	f.Add("0000XSNJG0MQJHBF4QX1EFD6Y3")
-	f.Add("01ARZ3NDEKTSV4RRFFQ69G5FAV")
-	f.Fuzz(func(t *testing.T, s string) {
-		id0, err := Parse(s)
-		if err != nil {
-			t.Skip()
+		// about 584 years in the future; the gap in nanoseconds wraps around to nearly zero in int64.
+		id.SetTime(now + 18446744073710)
+		if id.PlausibleMake(time.Minute) {
+			t.Fatal("far future: want false, got true")
 		}
-		id1, err := Parse(id0.String())
-		if err != nil {
-			t.Fatal(err)
+		id.SetTime(1<<48 - 1)
+		if id.PlausibleMake(time.Minute) {
+			t.Fatal("max time: want false, got true")
 		}
-		if id0 != id1 {
-			t.Fatalf("id0=%v id1=%v", id0, id1)
+
+		if MinForTime(now).PlausibleMake(time.Minute) {
+			t.Fatal("zero entropy: want false, got true")
+		}
+		if MaxForTime(now).PlausibleMake(time.Minute) {
+			t.Fatal("max entropy: want false, got true")
 		}
 	})
 }
 
-func TestString(t *testing.T) {
-	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	if id.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
-		t.Fatalf("id=%s", id.String())
+func TestFromSequence(t *testing.T) {
+	id := FromSequence(0x0123456789abcdef, 0x1563e3ab5d3)
+	if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}) {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+	if seq := binary.BigEndian.Uint64(id[8:]); seq != 0x0123456789abcdef {
+		t.Fatalf("seq=%x", seq)
 	}
 }
 
-func TestMarshalText(t *testing.T) {
+func TestTimeToken(t *testing.T) {
 	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	data, err := id.MarshalText()
+	token := id.TimeToken()
+	if token != id.String()[:10] {
+		t.Fatalf("token=%s", token)
+	}
+	ms, err := ParseTimeToken(token)
 	if err != nil {
 		t.Fatal(err)
 	}
-	want := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
-	if !bytes.Equal(data, want) {
-		t.Fatalf("data=%s", data)
+	if ms != id.Time() {
+		t.Fatalf("want %d, got %d", id.Time(), ms)
 	}
-}
 
-func TestUnmarshalText(t *testing.T) {
-	t.Run("valid", func(t *testing.T) {
-		data := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
-		var id ULID
-		if err := id.UnmarshalText(data); err != nil {
-			t.Fatal(err)
-		}
-		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-		if id != want {
-			t.Fatalf("want %v, got %v", want, id)
-		}
-	})
+	// Test that the tokens sort in the same order as the time components.
+	if MinForTime(1<<40).TimeToken() >= MinForTime(1<<40+1).TimeToken() {
+		t.Fatal("the tokens are not ordered")
+	}
+	if tok := MaxForTime(1<<48 - 1).TimeToken(); tok != "7ZZZZZZZZZ" {
+		t.Fatalf("token=%s", tok)
+	}
+}
 
-	t.Run("invalid size", func(t *testing.T) {
-		data := []byte("01ARZ3NDEKTSV4RRFFQ69G5FA")
-		var id ULID
-		err := id.UnmarshalText(data)
-		if !errors.Is(err, ErrInvalidSize) {
-			t.Fatal(err)
-		}
-	})
+func TestParseTimeToken_Invalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"01ARZ3NDE", ErrInvalidSize},
+		{"01ARZ3NDEKT", ErrInvalidSize},
+		{"01ARZ3NDEU", ErrInvalidCharacter},
+		{"8000000000", ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if _, err := ParseTimeToken(tt.s); err != tt.err {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+		})
+	}
 }
 
-func TestAppendText(t *testing.T) {
+func TestCompactEntropy(t *testing.T) {
 	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
-	data, err := id.AppendText(nil)
+	s := id.CompactEntropy()
+	if s != "TSV4RRFFQ69G5FAV" {
+		t.Fatalf("s=%s", s)
+	}
+
+	got, err := FromCompactEntropy(s, 0x1563e3ab5d3)
 	if err != nil {
 		t.Fatal(err)
 	}
-	want := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
-	if !bytes.Equal(data, want) {
-		t.Fatalf("data=%s", data)
+	if got != id {
+		t.Fatalf("want %v, got %v", id, got)
 	}
-}
 
-func BenchmarkString(b *testing.B) {
-	id := Make()
-	for b.Loop() {
-		runtime.KeepAlive(id.String())
+	// the entropy is independent of the time component.
+	got, err = FromCompactEntropy(s, 0)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func BenchmarkMarshalText(b *testing.B) {
-	id := Make()
-	for b.Loop() {
-		s, err := id.MarshalText()
-		if err != nil {
-			b.Fatal(err)
-		}
-		runtime.KeepAlive(s)
+	if got.Time() != 0 || !got.SameEntropy(id) {
+		t.Fatalf("got %v", got)
+	}
+	if got, err := FromCompactEntropy(strings.ToLower(s), 0x1563e3ab5d3); err != nil || got != id {
+		t.Fatalf("lower case: got %v, err=%v", got, err)
 	}
 }
 
-func TestIsZero(t *testing.T) {
-	if !Zero.IsZero() {
-		t.Fatalf("Zero.IsZero()=%v", Zero.IsZero())
+func TestFromCompactEntropy_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		ms   int64
+		err  error
+	}{
+		{"too short", "TSV4RRFFQ69G5FA", 0, ErrInvalidSize},
+		{"too long", "TSV4RRFFQ69G5FAVV", 0, ErrInvalidSize},
+		{"invalid character", "TSV4RRFFQ69G5FAU", 0, ErrInvalidCharacter},
+		{"time overflow", "TSV4RRFFQ69G5FAV", 1 << 48, ErrOverflow},
+		{"negative time", "TSV4RRFFQ69G5FAV", -1, ErrOverflow},
 	}
-	if Make().IsZero() {
-		t.Fatalf("Make().IsZero()=%v", Make().IsZero())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromCompactEntropy(tt.s, tt.ms); err != tt.err {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+		})
 	}
 }
 
-func BenchmarkIsZero(b *testing.B) {
-	id := Make()
+func TestAge(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		id := Make()
+		time.Sleep(90 * time.Second)
+		if age := id.Age(); age != 90*time.Second {
+			t.Fatalf("age=%v", age)
+		}
+	})
+}
+
+func TestAgeString(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{0, "0ms"},
+		{999 * time.Millisecond, "999ms"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 59*time.Second, "3m"},
+		{2*time.Hour + 30*time.Minute, "2h"},
+		{5*24*time.Hour + 23*time.Hour, "5d"},
+		{-3 * time.Minute, "-3m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				var id ULID
+				id.SetTime(time.Now().Add(-tt.age).UnixMilli())
+				if got := id.AgeString(); got != tt.want {
+					t.Fatalf("want %s, got %s", tt.want, got)
+				}
+			})
+		})
+	}
+}
+
+func TestInt64Pair(t *testing.T) {
+	tests := []struct {
+		id     ULID
+		hi, lo int64
+	}{
+		{
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0xcc, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			0x01563e3ab5d3d676,
+			-0x339e10466cfd42a5, // 0xcc61efb99302bd5b as a signed integer
+		},
+		{
+			ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			-1,
+			-1,
+		},
+		{
+			ULID{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			math.MinInt64,
+			math.MaxInt64,
+		},
+		{Zero, 0, 0},
+	}
+	for _, tt := range tests {
+		hi, lo := tt.id.Int64Pair()
+		if hi != tt.hi || lo != tt.lo {
+			t.Errorf("%x: hi=%x lo=%x, want hi=%x lo=%x", [16]byte(tt.id), hi, lo, tt.hi, tt.lo)
+		}
+		if got := FromInt64Pair(hi, lo); got != tt.id {
+			t.Errorf("FromInt64Pair(%x, %x)=%x, want %x", hi, lo, [16]byte(got), [16]byte(tt.id))
+		}
+	}
+}
+
+func TestMinForTime(t *testing.T) {
+	id := MinForTime(0x1563e3ab5d3)
+	if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+}
+
+func TestMaxForTime(t *testing.T) {
+	id := MaxForTime(0x1563e3ab5d3)
+	if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+}
+
+func TestFirstInMillis(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	got := id.FirstInMillis()
+	if got != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+		t.Fatalf("got=%x", [16]byte(got))
+	}
+}
+
+func TestLastInMillis(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	got := id.LastInMillis()
+	if got != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		t.Fatalf("got=%x", [16]byte(got))
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		// a synthetic sorted set of ULIDs, two per millisecond
+		var ids []ULID
+		for ms := int64(1000); ms < 1010; ms++ {
+			ids = append(ids, MinForTime(ms), MaxForTime(ms))
+		}
+
+		lo, hi := TimeRange(1003, 1005)
+		var got []int64
+		for _, id := range ids {
+			if id.Compare(lo) >= 0 && id.Compare(hi) <= 0 {
+				got = append(got, id.Time())
+			}
+		}
+		want := []int64{1003, 1003, 1004, 1004, 1005, 1005}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		TimeRange(1005, 1003)
+	})
+}
+
+func TestBucketBounds(t *testing.T) {
+	collect := func(startMs, endMs, stepMs int64) [][2]int64 {
+		var got [][2]int64
+		for lo, hi := range BucketBounds(startMs, endMs, stepMs) {
+			if lo != MinForTime(lo.Time()) || hi != MaxForTime(hi.Time()) {
+				t.Fatalf("lo=%v hi=%v", lo, hi)
+			}
+			got = append(got, [2]int64{lo.Time(), hi.Time()})
+		}
+		return got
+	}
+
+	tests := []struct {
+		name                   string
+		startMs, endMs, stepMs int64
+		want                   [][2]int64
+	}{
+		{"exact", 1000, 3999, 1000, [][2]int64{{1000, 1999}, {2000, 2999}, {3000, 3999}}},
+		{"not exact", 1000, 3499, 1000, [][2]int64{{1000, 1999}, {2000, 2999}, {3000, 3499}}},
+		{"one millisecond", 1000, 1000, 1000, [][2]int64{{1000, 1000}}},
+		{"step of one", 1000, 1002, 1, [][2]int64{{1000, 1000}, {1001, 1001}, {1002, 1002}}},
+		{"empty", 1000, 999, 1000, nil},
+		{"up to the max", 1<<48 - 3, 1<<48 - 1, 2, [][2]int64{{1<<48 - 3, 1<<48 - 2}, {1<<48 - 1, 1<<48 - 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collect(tt.startMs, tt.endMs, tt.stepMs)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("break", func(t *testing.T) {
+		n := 0
+		for range BucketBounds(0, 9999, 1000) {
+			n++
+			if n == 2 {
+				break
+			}
+		}
+		if n != 2 {
+			t.Fatalf("n=%d", n)
+		}
+	})
+
+	t.Run("invalid step", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		BucketBounds(0, 9999, 0)
+	})
+}
+
+func TestSetTime(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var id ULID
+		id.SetTime(0x1563e3ab5d3)
+		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("invalid negative", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		var id ULID
+		id.SetTime(-1)
+	})
+
+	t.Run("max valid", func(t *testing.T) {
+		var id ULID
+		id.SetTime(0xFFFFFFFFFFFF)
+		if id != (ULID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("invalid overflow", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		var id ULID
+		id.SetTime(0x1000000000000)
+	})
+}
+
+func TestSetTimeSafe(t *testing.T) {
+	tests := []struct {
+		ms  int64
+		err error
+	}{
+		{0, nil},
+		{1469922850259, nil},
+		{1<<48 - 1, nil},
+		{1 << 48, ErrOverflow},
+		{-1, ErrOverflow},
+	}
+	for _, tt := range tests {
+		id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		orig := id
+		err := id.SetTimeSafe(tt.ms)
+		if err != tt.err {
+			t.Errorf("SetTimeSafe(%d): want %v, got %v", tt.ms, tt.err, err)
+			continue
+		}
+		if err != nil {
+			if id != orig {
+				t.Errorf("SetTimeSafe(%d): id was modified: %v", tt.ms, id)
+			}
+			continue
+		}
+		if id.Time() != tt.ms {
+			t.Errorf("SetTimeSafe(%d): time=%d", tt.ms, id.Time())
+		}
+		id2, err := Parse(id.String())
+		if err != nil {
+			t.Errorf("SetTimeSafe(%d): %s does not round-trip: %v", tt.ms, id, err)
+			continue
+		}
+		if id2 != id {
+			t.Errorf("SetTimeSafe(%d): want %v, got %v", tt.ms, id, id2)
+		}
+	}
+}
+
+func TestClampTime(t *testing.T) {
+	const minMs, maxMs = 1469922850000, 1469922850999
+	tests := []struct {
+		name string
+		ms   int64
+		want int64
+	}{
+		{"below min", 0, minMs},
+		{"min", minMs, minMs},
+		{"in range", 1469922850259, 1469922850259},
+		{"max", maxMs, maxMs},
+		{"above max", 1<<48 - 1, maxMs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+			id.SetTime(tt.ms)
+			got := id.ClampTime(minMs, maxMs)
+			if got.Time() != tt.want {
+				t.Fatalf("want %d, got %d", tt.want, got.Time())
+			}
+			if !got.SameEntropy(id) {
+				t.Fatalf("the random component changed: %v", got)
+			}
+		})
+	}
+
+	t.Run("invalid window", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		Zero.ClampTime(maxMs, minMs)
+	})
+}
+
+func TestWithTimeAfter(t *testing.T) {
+	ref := MinForTime(1000)
+	tests := []struct {
+		name string
+		ms   int64
+		want int64
+	}{
+		{"earlier", 999, 1001},
+		{"equal", 1000, 1001},
+		{"later", 1001, 1001},
+		{"much later", 2000, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := FromSequence(0x0123456789abcdef, tt.ms)
+			got, err := id.WithTimeAfter(ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Time() != tt.want {
+				t.Fatalf("time=%d, want %d", got.Time(), tt.want)
+			}
+			if !got.SameEntropy(id) {
+				t.Fatalf("entropy is not preserved: %v", got)
+			}
+			if got.Compare(ref) <= 0 {
+				t.Fatalf("%v does not sort after %v", got, ref)
+			}
+		})
+	}
+
+	t.Run("overflow", func(t *testing.T) {
+		_, err := Zero.WithTimeAfter(MinForTime(0xFFFFFFFFFFFF))
+		if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestTime(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if id.Time() != 0x1563e3ab5d3 {
+		t.Fatalf("time=%x", id.Time())
+	}
+}
+
+func TestUnixSeconds(t *testing.T) {
+	tests := []struct {
+		ms   int64
+		want int64
+	}{
+		{0, 0},
+		{999, 0},
+		{1000, 1},
+		{1469922849999, 1469922849},
+		{1469922850000, 1469922850},
+		{1469922850259, 1469922850},
+	}
+	for _, tt := range tests {
+		var id ULID
+		id.SetTime(tt.ms)
+		if got := id.UnixSeconds(); got != tt.want {
+			t.Errorf("UnixSeconds() of %d = %d, want %d", tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want float64
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 0x1p-80},
+		{1000, 999000 * 0x1p-81},
+		{1 << 40, 0.3934693402873666}, // 1 - exp(-1/2)
+		{1 << 45, 1},
+	}
+	for _, tt := range tests {
+		if tt.n > math.MaxInt {
+			continue // int is 32 bits
+		}
+		got := CollisionProbability(int(tt.n))
+		if math.Abs(got-tt.want) > tt.want*1e-9 {
+			t.Errorf("CollisionProbability(%d)=%g, want %g", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMaxPerMillisecond(t *testing.T) {
+	if got := MaxPerMillisecond().String(); got != "1208925819614629174706176" {
+		t.Fatalf("got %s", got)
+	}
+	if MaxPerMillisecond().Cmp(CountInTimeRange(0, 0)) != 0 {
+		t.Fatal("MaxPerMillisecond and CountInTimeRange disagree")
+	}
+}
+
+func TestCountInTimeRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		startMs, endMs int64
+		want           string
+	}{
+		{"one millisecond", 1469922850259, 1469922850259, "1208925819614629174706176"}, // 2^80
+		{"one second", 1469922850000, 1469922850999, "1208925819614629174706176000"},   // 1000 * 2^80
+		{"all", 0, 1<<48 - 1, "340282366920938463463374607431768211456"},               // 2^128
+		{"empty", 1469922850259, 1469922850258, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CountInTimeRange(tt.startMs, tt.endMs)
+			if got.String() != tt.want {
+				t.Fatalf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	lo, hi := TimeRange(1469922850000, 1469922850999)
+	mid := MinForTime(1469922850500)
+	tests := []struct {
+		name string
+		id   ULID
+		want float64
+	}{
+		{"lo", lo, 0},
+		{"hi", hi, 1},
+		{"mid", mid, 0.5},
+		{"before", Zero, 0},
+		{"after", MaxForTime(1469922851000), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Progress(tt.id, lo, hi)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("want %g, got %g", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("lo equals hi", func(t *testing.T) {
+		if got := Progress(mid, mid, mid); got != 1 {
+			t.Fatalf("want 1, got %g", got)
+		}
+		if got := Progress(lo, mid, mid); got != 0 {
+			t.Fatalf("want 0, got %g", got)
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("did not panic")
+			}
+		}()
+		Progress(mid, hi, lo)
+	})
+}
+
+func TestPartition(t *testing.T) {
+	check := func(t *testing.T, lo, hi ULID, n int, parts [][2]ULID) {
+		t.Helper()
+		if len(parts) != n {
+			t.Fatalf("len=%d, want %d", len(parts), n)
+		}
+		if parts[0][0] != lo {
+			t.Fatalf("the first part starts at %v, want %v", parts[0][0], lo)
+		}
+		if parts[n-1][1] != hi {
+			t.Fatalf("the last part ends at %v, want %v", parts[n-1][1], hi)
+		}
+		for i, p := range parts {
+			if p[0].Compare(p[1]) >= 0 {
+				t.Fatalf("parts[%d] is empty: %v", i, p)
+			}
+			if i > 0 && parts[i-1][1] != p[0] {
+				t.Fatalf("parts[%d] and parts[%d] are not contiguous: %v, %v", i-1, i, parts[i-1], p)
+			}
+		}
+	}
+
+	t.Run("time range", func(t *testing.T) {
+		lo, hi := MinForTime(1000), MinForTime(2000)
+		parts := Partition(lo, hi, 4)
+		check(t, lo, hi, 4, parts)
+		for i, want := range []int64{1000, 1250, 1500, 1750} {
+			if parts[i][0] != MinForTime(want) {
+				t.Fatalf("parts[%d] starts at %v, want %v", i, parts[i][0], MinForTime(want))
+			}
+		}
+	})
+
+	t.Run("uneven", func(t *testing.T) {
+		lo, hi := Zero, ULID{15: 10}
+		parts := Partition(lo, hi, 3)
+		check(t, lo, hi, 3, parts)
+		for i, want := range []byte{0, 3, 6} {
+			if parts[i][0] != (ULID{15: want}) {
+				t.Fatalf("parts[%d] starts at %v", i, parts[i][0])
+			}
+		}
+	})
+
+	t.Run("whole key space", func(t *testing.T) {
+		max := ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+		parts := Partition(Zero, max, 16)
+		check(t, Zero, max, 16, parts)
+		if parts[8][0] != (ULID{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			t.Fatalf("parts[8] starts at %x", [16]byte(parts[8][0]))
+		}
+	})
+
+	t.Run("more parts than ULIDs", func(t *testing.T) {
+		lo, hi := MinForTime(1000), MinForTime(1000).Next().Next()
+		parts := Partition(lo, hi, 10)
+		check(t, lo, hi, 2, parts)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		lo, hi := MinForTime(1000), MinForTime(2000)
+		if parts := Partition(lo, hi, 0); parts != nil {
+			t.Fatalf("parts=%v", parts)
+		}
+		if parts := Partition(lo, hi, -1); parts != nil {
+			t.Fatalf("parts=%v", parts)
+		}
+		if parts := Partition(lo, lo, 4); parts != nil {
+			t.Fatalf("parts=%v", parts)
+		}
+		if parts := Partition(hi, lo, 4); parts != nil {
+			t.Fatalf("parts=%v", parts)
+		}
+	})
+}
+
+func TestSince(t *testing.T) {
+	var a, b ULID
+	a.SetTime(1469918176385)
+
+	b.SetTime(1469918176385)
+	if d := Since(a, b); d != 0 {
+		t.Fatalf("same millisecond: d=%v", d)
+	}
+
+	b.SetTime(1469918176385 + 24*60*60*1000 + 1)
+	if d := Since(a, b); d != 24*time.Hour+time.Millisecond {
+		t.Fatalf("across days: d=%v", d)
+	}
+	if d := Since(b, a); d != -(24*time.Hour + time.Millisecond) {
+		t.Fatalf("reversed: d=%v", d)
+	}
+}
+
+func TestWithinMillis(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		tol  int64
+		want bool
+	}{
+		{1000, 1000, 0, true},
+		{1000, 1001, 0, false},
+		{1000, 1005, 5, true},
+		{1005, 1000, 5, true},
+		{1000, 1006, 5, false},
+		{1006, 1000, 5, false},
+		{0, 0xFFFFFFFFFFFF, 0xFFFFFFFFFFFF, true},
+	}
+	for _, tt := range tests {
+		a := FromSequence(1, tt.a)
+		b := FromSequence(2, tt.b)
+		if got := a.WithinMillis(b, tt.tol); got != tt.want {
+			t.Errorf("a=%d b=%d tol=%d: want %v, got %v", tt.a, tt.b, tt.tol, tt.want, got)
+		}
+	}
+}
+
+func TestSameBucket(t *testing.T) {
+	// 2016-07-30T23:00:00Z and 2016-07-31T00:00:00Z
+	const hour, day = 1469919600000, 1469923200000
+	tests := []struct {
+		name string
+		a, b int64
+		d    time.Duration
+		want bool
+	}{
+		{"same hour", hour, hour + 3599999, time.Hour, true},
+		{"across an hour boundary", hour - 1, hour, time.Hour, false},
+		{"same day", day - 86400000, day - 1, 24 * time.Hour, true},
+		{"across a day boundary", day - 1, day, 24 * time.Hour, false},
+		{"same millisecond", hour, hour, 0, true},
+		{"different milliseconds", hour, hour + 1, 0, false},
+		{"negative duration", hour, hour + 1, -time.Hour, false},
+		{"sub-millisecond duration", hour, hour + 1, time.Microsecond, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := MinForTime(tt.a), MaxForTime(tt.b)
+			if got := a.SameBucket(b, tt.d); got != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+			if got := b.SameBucket(a, tt.d); got != tt.want {
+				t.Fatalf("not symmetric: want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIncEntropy(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ULID
+		want ULID
+		ok   bool
+	}{
+		{
+			name: "simple",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5c},
+			ok:   true,
+		},
+		{
+			name: "carry into the high bits",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			ok:   true,
+		},
+		{
+			name: "max entropy",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			ok:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.in.IncEntropy()
+			if ok != tt.ok {
+				t.Fatalf("ok=%v", ok)
+			}
+			if got != tt.want {
+				t.Fatalf("want %x, got %x", [16]byte(tt.want), [16]byte(got))
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ULID
+		want ULID
+	}{
+		{
+			name: "simple",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5c},
+		},
+		{
+			name: "carry into the timestamp",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd4, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name: "wrap around",
+			in:   ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: Zero,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in.Next()
+			if got != tt.want {
+				t.Fatalf("want %x, got %x", [16]byte(tt.want), [16]byte(got))
+			}
+		})
+	}
+}
+
+func TestPrev(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ULID
+		want ULID
+	}{
+		{
+			name: "simple",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5a},
+		},
+		{
+			name: "borrow across a byte",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x00},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbc, 0xff},
+		},
+		{
+			name: "borrow from the timestamp",
+			in:   ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd4, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			want: ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name: "wrap around",
+			in:   Zero,
+			want: ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in.Prev()
+			if got != tt.want {
+				t.Fatalf("want %x, got %x", [16]byte(tt.want), [16]byte(got))
+			}
+			if got.Next() != tt.in {
+				t.Fatalf("Next did not undo Prev: %x", [16]byte(got.Next()))
+			}
+		})
+	}
+}
+
+func TestBefore(t *testing.T) {
+	ref := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0x00, 0x00}
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x01, 0xff, 0xff}
+	if got := Before(ref); got != want {
+		t.Fatalf("want %x, got %x", [16]byte(want), [16]byte(got))
+	}
+	if got := Before(Zero); got != Zero {
+		t.Fatalf("want Zero, got %x", [16]byte(got))
+	}
+}
+
+func TestImmediatelyPrecedes(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
+	next := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	carried := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd4, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	max := ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+	tests := []struct {
+		name string
+		a, b ULID
+		want bool
+	}{
+		{"adjacent", id, next, true},
+		{"adjacent across the timestamp", next, carried, true},
+		{"gap", id, carried, false},
+		{"same", id, id, false},
+		{"reversed", next, id, false},
+		{"max", max, Zero, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.ImmediatelyPrecedes(tt.b); got != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data, err := id.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%x", data)
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		var id ULID
+		if err := id.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd}
+		var id ULID
+		err := id.UnmarshalBinary(data)
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatal(err)
+		}
+	})
+
+}
+
+func TestReadFrom(t *testing.T) {
+	t.Run("exact", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		var id ULID
+		n, err := id.ReadFrom(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != BinarySize {
+			t.Fatalf("n=%d", n)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		data := []byte{
+			0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b,
+			0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5c,
+		}
+		r := bytes.NewReader(data)
+		var id1, id2, id3 ULID
+		if _, err := id1.ReadFrom(r); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := id2.ReadFrom(r); err != nil {
+			t.Fatal(err)
+		}
+		if id1.Compare(id2) >= 0 {
+			t.Fatalf("id1=%v, id2=%v", id1, id2)
+		}
+		if n, err := id3.ReadFrom(r); err != io.EOF || n != 0 {
+			t.Fatalf("n=%d, err=%v", n, err)
+		}
+	})
+
+	t.Run("short", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd}
+		var id ULID
+		n, err := id.ReadFrom(bytes.NewReader(data))
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatal(err)
+		}
+		if n != 15 {
+			t.Fatalf("n=%d", n)
+		}
+		if !id.IsZero() {
+			t.Fatalf("id was modified: %v", id)
+		}
+	})
+}
+
+func TestWriteTo(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	var buf bytes.Buffer
+	n, err := id.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != BinarySize {
+		t.Fatalf("n=%d", n)
+	}
+
+	var id2 ULID
+	if _, err := id2.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if id != id2 {
+		t.Fatalf("want %v, got %v", id, id2)
+	}
+}
+
+func TestMarshalBinaryLE(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data, err := id.MarshalBinaryLE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x5b, 0xbd, 0x02, 0x93, 0xb9, 0xef, 0x61, 0x4c, 0x76, 0xd6, 0xd3, 0xb5, 0x3a, 0x3e, 0x56, 0x01}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%x", data)
+	}
+
+	// Test that the LE form is the byte-reverse of the canonical form.
+	be, err := id.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Reverse(be)
+	if !bytes.Equal(data, be) {
+		t.Fatalf("data=%x, reversed canonical=%x", data, be)
+	}
+}
+
+func TestUnmarshalBinaryLE(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		data := []byte{0x5b, 0xbd, 0x02, 0x93, 0xb9, 0xef, 0x61, 0x4c, 0x76, 0xd6, 0xd3, 0xb5, 0x3a, 0x3e, 0x56, 0x01}
+		var id ULID
+		if err := id.UnmarshalBinaryLE(data); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		var id ULID
+		err := id.UnmarshalBinaryLE(make([]byte, 15))
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestUnmarshalBinaryStrict(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		var id ULID
+		if err := id.UnmarshalBinaryStrict(data); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		data := make([]byte, 16)
+		var id ULID
+		err := id.UnmarshalBinaryStrict(data)
+		if !errors.Is(err, ErrZero) {
+			t.Fatal(err)
+		}
+
+		// the permissive variant accepts the zero ULID.
+		if err := id.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		data := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd}
+		var id ULID
+		err := id.UnmarshalBinaryStrict(data)
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestAppendBinary(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data, err := id.AppendBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%x", data)
+	}
+
+	// Test that the encoding is big-endian: the time component comes first, MSB first.
+	var ms [8]byte
+	copy(ms[2:], data[:6])
+	if binary.BigEndian.Uint64(ms[:]) != uint64(id.Time()) {
+		t.Fatalf("the time component is not big-endian: %x", data)
+	}
+}
+
+func TestMarshalBinaryTo(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data := id.MarshalBinaryTo([]byte{0xff})
+	want := []byte{0xff, 0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%x", data)
+	}
+}
+
+func BenchmarkMarshalBinaryTo(b *testing.B) {
+	id := Make()
+	buf := make([]byte, 0, BinarySize)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = id.MarshalBinaryTo(buf[:0])
+	}
+	runtime.KeepAlive(buf)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid ulid", func(t *testing.T) {
+		id, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("lower case ulid", func(t *testing.T) {
+		id, err := Parse("01arz3ndektsv4rrffq69g5fav")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FA")
+		if err != ErrInvalidSize {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := Parse("")
+		if err != ErrEmpty {
+			t.Fatalf("err=%v", err)
+		}
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("near-length", func(t *testing.T) {
+		for _, s := range []string{"0", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAV0"} {
+			_, err := Parse(s)
+			if err != ErrInvalidSize {
+				t.Fatalf("%q: err=%v", s, err)
+			}
+		}
+	})
+
+	t.Run("huge", func(t *testing.T) {
+		// The characters are all invalid, so ErrInvalidSize proves that the length is checked before scanning.
+		s := strings.Repeat("!", 1<<20)
+		if _, err := Parse(s); err != ErrInvalidSize {
+			t.Fatalf("err=%v", err)
+		}
+		var id ULID
+		if err := id.UnmarshalText([]byte(s)); err != ErrInvalidSize {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("invalid character", func(t *testing.T) {
+		_, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FA!")
+		if err != ErrInvalidCharacter {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		_, err := Parse("80000000000000000000000000")
+		if err != ErrOverflow {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestParseAfter(t *testing.T) {
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV" // 0x1563e3ab5d3 ms
+	t.Run("below", func(t *testing.T) {
+		_, err := ParseAfter(s, 0x1563e3ab5d4)
+		if !errors.Is(err, ErrTooOld) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		id, err := ParseAfter(s, 0x1563e3ab5d3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.String() != s {
+			t.Fatalf("id=%v", id)
+		}
+	})
+
+	t.Run("above", func(t *testing.T) {
+		_, err := ParseAfter(s, 0x1563e3ab5d2)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseAfter("01ARZ3NDEKTSV4RRFFQ69G5FA", 0)
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestParseLine(t *testing.T) {
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	valid := []string{
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV\n",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV\r\n",
+	}
+	for _, s := range valid {
+		id, err := ParseLine(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if id != want {
+			t.Fatalf("%q: want %v, got %v", s, want, id)
+		}
+	}
+
+	invalid := []string{
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV\n\n",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV \n",
+		"01ARZ3NDEKTSV4RRFFQ69G5FA\n",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV\r",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV\n\r",
+	}
+	for _, s := range invalid {
+		if _, err := ParseLine(s); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("%q: err=%v", s, err)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	const key = "GO_ULID_TEST_FROM_ENV"
+
+	t.Run("set", func(t *testing.T) {
+		t.Setenv(key, " 01ARZ3NDEKTSV4RRFFQ69G5FAV\n")
+		id, err := FromEnv(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+		_, err := FromEnv(key)
+		if err == nil {
+			t.Fatal("want error, got nil")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Setenv(key, "01ARZ3NDEKTSV4RRFFQ69G5FA!")
+		_, err := FromEnv(key)
+		if !errors.Is(err, ErrInvalidCharacter) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Setenv(key, "")
+		_, err := FromEnv(key)
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestParseAuto(t *testing.T) {
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("binary", func(t *testing.T) {
+		id, err := ParseAuto([]byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		id, err := ParseAuto([]byte("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("invalid text", func(t *testing.T) {
+		_, err := ParseAuto([]byte("01ARZ3NDEKTSV4RRFFQ69G5FA!"))
+		if !errors.Is(err, ErrInvalidCharacter) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		for _, n := range []int{0, 15, 17, 25, 27} {
+			_, err := ParseAuto(make([]byte, n))
+			if !errors.Is(err, ErrInvalidSize) {
+				t.Fatalf("len=%d: err=%v", n, err)
+			}
+		}
+	})
+}
+
+func TestFindAll(t *testing.T) {
+	id1 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id2 := ULID{0x00, 0x00, 0x3b, 0x9a, 0xca, 0x00, 0xa5, 0xe5, 0x15, 0xbc, 0x97, 0xe8, 0x5c, 0xf6, 0x9b, 0xc3}
+
+	tests := []struct {
+		name string
+		s    string
+		want []ULID
+	}{
+		{
+			name: "empty",
+			s:    "",
+			want: nil,
+		},
+		{
+			name: "exact",
+			s:    "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			want: []ULID{id1},
+		},
+		{
+			name: "multiple",
+			s:    "request_id=01ARZ3NDEKTSV4RRFFQ69G5FAV user=0000XSNJG0MQJHBF4QX1EFD6Y3 status=ok",
+			want: []ULID{id1, id2},
+		},
+		{
+			name: "adjacent",
+			s:    "01ARZ3NDEKTSV4RRFFQ69G5FAV,0000XSNJG0MQJHBF4QX1EFD6Y3",
+			want: []ULID{id1, id2},
+		},
+		{
+			name: "no separator",
+			s:    "01ARZ3NDEKTSV4RRFFQ69G5FAV0000XSNJG0MQJHBF4QX1EFD6Y3",
+			want: nil,
+		},
+		{
+			name: "lower case",
+			s:    "id=01arz3ndektsv4rrffq69g5fav;",
+			want: []ULID{id1},
+		},
+		{
+			name: "too short",
+			s:    "id=01ARZ3NDEKTSV4RRFFQ69G5FA id=01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			want: []ULID{id1},
+		},
+		{
+			name: "invalid character",
+			s:    "01ARZ3NDEKTSV4RRFFQ69G5FU!",
+			want: nil,
+		},
+		{
+			name: "overflow",
+			s:    "Z1ARZ3NDEKTSV4RRFFQ69G5FA-",
+			want: nil,
+		},
+		{
+			name: "leading alphabet characters",
+			s:    "0001ARZ3NDEKTSV4RRFFQ69G5FAV",
+			want: nil,
+		},
+		{
+			name: "trailing alphabet characters",
+			s:    "01ARZ3NDEKTSV4RRFFQ69G5FAV0",
+			want: nil,
+		},
+		{
+			name: "hex trace id",
+			s:    "trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7",
+			want: nil,
+		},
+		{
+			name: "between non-alphabet characters",
+			s:    "(01ARZ3NDEKTSV4RRFFQ69G5FAV)",
+			want: []ULID{id1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindAll(tt.s)
+			if len(got) != len(tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("want %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	const s = "0000XSNJG0MQJHBF4QX1EFD6Y3"
+	b.ReportAllocs()
+	for b.Loop() {
+		id, err := Parse(s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		runtime.KeepAlive(id)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("0000XSNJG0MQJHBF4QX1EFD6Y3")
+	f.Add("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	f.Add(strings.Repeat("0", 1<<20))
+	f.Fuzz(func(t *testing.T, s string) {
+		id0, err := Parse(s)
+		if err != nil {
+			t.Skip()
+		}
+		id1, err := Parse(id0.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id0 != id1 {
+			t.Fatalf("id0=%v id1=%v", id0, id1)
+		}
+	})
+}
+
+func FuzzBinaryText(f *testing.F) {
+	f.Add(make([]byte, 16))
+	f.Add(bytes.Repeat([]byte{0xff}, 16))
+	f.Add([]byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var id0 ULID
+		if err := id0.UnmarshalBinary(data); err != nil {
+			t.Skip()
+		}
+		text, err := id0.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 16 bytes are 128 bits, and 26 characters of base32 are 130 bits.
+		// The first character carries only the top 3 bits, so it never exceeds '7'
+		// and every binary value has a valid text representation.
+		if text[0] > '7' {
+			t.Fatalf("the first character overflows: %s", text)
+		}
+		id1, err := Parse(string(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id0 != id1 {
+			t.Fatalf("id0=%v id1=%v", id0, id1)
+		}
+	})
+}
+
+func TestString(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if id.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatalf("id=%s", id.String())
+	}
+}
+
+func TestStringWithChecksum(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	s := id.StringWithChecksum()
+	if s != "01ARZ3NDEKTSV4RRFFQ69G5FAVQ" {
+		t.Fatalf("s=%s", s)
+	}
+	if s := Zero.StringWithChecksum(); s != "000000000000000000000000000" {
+		t.Fatalf("s=%s", s)
+	}
+}
+
+func TestParseWithChecksum(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ParseWithChecksum("01ARZ3NDEKTSV4RRFFQ69G5FAVQ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != (ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}) {
+			t.Fatalf("id=%x", [16]byte(id))
+		}
+	})
+
+	t.Run("lower case", func(t *testing.T) {
+		_, err := ParseWithChecksum("01arz3ndektsv4rrffq69g5favq")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("single-character corruption", func(t *testing.T) {
+		const s = "01ARZ3NDEKTSV4RRFFQ69G5FAVQ"
+		for i := range len(s) {
+			for j := range len(enc) {
+				if s[i] == enc[j] || (i == 0 && enc[j] > '7') {
+					continue
+				}
+				corrupted := s[:i] + enc[j:j+1] + s[i+1:]
+				if _, err := ParseWithChecksum(corrupted); !errors.Is(err, ErrChecksum) {
+					t.Fatalf("%s: err=%v", corrupted, err)
+				}
+			}
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := ParseWithChecksum("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("invalid character", func(t *testing.T) {
+		_, err := ParseWithChecksum("01ARZ3NDEKTSV4RRFFQ69G5FAV!")
+		if !errors.Is(err, ErrInvalidCharacter) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestClaimString(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	claims := map[string]any{
+		"sub": "user",
+		"jti": id.ClaimString(),
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"jti":"01ARZ3NDEKTSV4RRFFQ69G5FAV","sub":"user"}` {
+		t.Fatalf("data=%s", data)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseClaim(decoded["jti"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != id {
+		t.Fatalf("want %v, got %v", id, got)
+	}
+}
+
+func TestParseClaim_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"missing", nil},
+		{"number", 1469922850259.0},
+		{"bytes", []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")},
+		{"lower case", "01arz3ndektsv4rrffq69g5fav"},
+		{"invalid", "01ARZ3NDEKTSV4RRFFQ69G5FAU"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseClaim(tt.v); err == nil {
+				t.Fatal("want error")
+			}
+		})
+	}
+}
+
+func TestStringGrouped(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	s := id.StringGrouped()
+	if s != "01ARZ3ND-EKTSV4RR-FFQ69G5F-AV" {
+		t.Fatalf("s=%s", s)
+	}
+	id2, err := ParseGrouped(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != id2 {
+		t.Fatalf("want %v, got %v", id, id2)
+	}
+}
+
+func TestParseGrouped(t *testing.T) {
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"01ARZ3ND-EKTSV4RR-FFQ69G5F-AV", nil},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", nil},
+		{"01arz3nd-ektsv4rr-ffq69g5f-av", nil},
+		{"01ARZ-3NDEK-TSV4R-RFFQ6-9G5FA-V", nil},
+		{"01ARZ3ND--EKTSV4RR-FFQ69G5F-AV-", nil},
+		{"01ARZ3ND-EKTSV4RR-FFQ69G5F-A", ErrInvalidSize},
+		{"01ARZ3ND-EKTSV4RR-FFQ69G5F-AVV", ErrInvalidSize},
+		{"01ARZ3ND EKTSV4RR FFQ69G5F AV", ErrInvalidSize},
+		{"--------", ErrEmpty},
+		{"01ARZ3ND-EKTSV4RR-FFQ69G5F-AU", ErrInvalidCharacter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			id, err := ParseGrouped(tt.s)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+			if err == nil && id != want {
+				t.Fatalf("want %v, got %v", want, id)
+			}
+		})
+	}
+}
+
+func TestHex(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	s := id.Hex()
+	if s != "01563e3ab5d3d6764c61efb99302bd5b" {
+		t.Fatalf("s=%s", s)
+	}
+	data := id.AppendHex([]byte("id="))
+	if string(data) != "id="+s {
+		t.Fatalf("data=%s", data)
+	}
+}
+
+func BenchmarkAppendHex(b *testing.B) {
+	id := Make()
+	buf := make([]byte, 0, 2*BinarySize)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = id.AppendHex(buf[:0])
+	}
+	runtime.KeepAlive(buf)
+}
+
+func TestGroupedHex(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	s := id.GroupedHex()
+	if s != "01:56:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5b" {
+		t.Fatalf("s=%s", s)
+	}
+	id2, err := ParseGroupedHex(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != id2 {
+		t.Fatalf("want %v, got %v", id, id2)
+	}
+
+	id2, err = ParseGroupedHex(strings.ToUpper(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != id2 {
+		t.Fatalf("want %v, got %v", id, id2)
+	}
+}
+
+func TestParseGroupedHex_Invalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"01:56:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5", ErrInvalidSize},
+		{"01:56:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5b:", ErrInvalidSize},
+		{"01-56-3e-3a-b5-d3-d6-76-4c-61-ef-b9-93-02-bd-5b", ErrInvalidCharacter},
+		{"01:56:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5g", ErrInvalidCharacter},
+		{"0:156:3e:3a:b5:d3:d6:76:4c:61:ef:b9:93:02:bd:5b", ErrInvalidCharacter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if _, err := ParseGroupedHex(tt.s); err != tt.err {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPathSegment(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	s := id.PathSegment()
+	if s != "01arz3ndektsv4rrffq69g5fav" {
+		t.Fatalf("s=%s", s)
+	}
+	if url.PathEscape(s) != s {
+		t.Fatalf("%s is not safe in a URL path", s)
+	}
+
+	id2, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != id2 {
+		t.Fatalf("want %v, got %v", id, id2)
+	}
+}
+
+func TestBase36(t *testing.T) {
+	tests := []struct {
+		id ULID
+		s  string
+	}{
+		{
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			"02UKOHBN3KW46609ND56RLKD7",
+		},
+		{
+			ULID{},
+			"0000000000000000000000000",
+		},
+		{
+			ULID{15: 0x01},
+			"0000000000000000000000001",
+		},
+		{
+			ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			"F5LXX1ZZ5PNORYNQGLHZMSP33",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			s := tt.id.Base36()
+			if s != tt.s {
+				t.Fatalf("want %s, got %s", tt.s, s)
+			}
+			id, err := ParseBase36(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.id {
+				t.Fatalf("want %v, got %v", tt.id, id)
+			}
+			id, err = ParseBase36(strings.ToLower(s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.id {
+				t.Fatalf("want %v, got %v", tt.id, id)
+			}
+		})
+	}
+}
+
+func TestParseBase36_Invalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"02UKOHBN3KW46609ND56RLKD", ErrInvalidSize},
+		{"02UKOHBN3KW46609ND56RLKD77", ErrInvalidSize},
+		{"+2UKOHBN3KW46609ND56RLKD7", ErrInvalidCharacter},
+		{"02UKOHBN3KW46609ND56RLKD_", ErrInvalidCharacter},
+		{"F5LXX1ZZ5PNORYNQGLHZMSP34", ErrOverflow},
+		{"ZZZZZZZZZZZZZZZZZZZZZZZZZ", ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			_, err := ParseBase36(tt.s)
+			if err != tt.err {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%s", data)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		data := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		var id ULID
+		if err := id.UnmarshalText(data); err != nil {
+			t.Fatal(err)
+		}
+		want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if id != want {
+			t.Fatalf("want %v, got %v", want, id)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		data := []byte("01ARZ3NDEKTSV4RRFFQ69G5FA")
+		var id ULID
+		err := id.UnmarshalText(data)
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestAppendText(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data, err := id.AppendText(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%s", data)
+	}
+}
+
+func TestAppendText_InPlace(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	buf := make([]byte, 0, 2*EncodedSize+1)
+	buf = append(buf, "prefix:"...)
+
+	data, err := id.AppendText(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &data[0] != &buf[:1][0] {
+		t.Fatal("AppendText reallocated the buffer")
+	}
+	if string(data) != "prefix:01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatalf("data=%s", data)
+	}
+
+	// exactly EncodedSize bytes of spare capacity
+	buf = make([]byte, 0, EncodedSize)
+	data, err = id.AppendText(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &data[0] != &buf[:1][0] {
+		t.Fatal("AppendText reallocated the buffer")
+	}
+}
+
+func TestAppendTextCase(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("upper", func(t *testing.T) {
+		data := id.AppendTextCase(nil, true)
+		want := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		if !bytes.Equal(data, want) {
+			t.Fatalf("data=%s", data)
+		}
+	})
+
+	t.Run("lower", func(t *testing.T) {
+		data := id.AppendTextCase(nil, false)
+		want := []byte("01arz3ndektsv4rrffq69g5fav")
+		if !bytes.Equal(data, want) {
+			t.Fatalf("data=%s", data)
+		}
+	})
+}
+
+func BenchmarkAppendTextCase(b *testing.B) {
+	id := Make()
+	buf := make([]byte, 0, EncodedSize)
+	for b.Loop() {
+		buf = id.AppendTextCase(buf[:0], false)
+	}
+	runtime.KeepAlive(buf)
+}
+
+func TestAppendJSON(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	data := id.AppendJSON([]byte("{\"id\":"))
+	want := []byte(`{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAV"`)
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%s", data)
+	}
+
+	// Test that the output is the same as encoding/json.
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := id.AppendJSON(nil); !bytes.Equal(got, data) {
+		t.Fatalf("want %s, got %s", data, got)
+	}
+}
+
+func BenchmarkAppendJSON(b *testing.B) {
+	id := Make()
+	buf := make([]byte, 0, EncodedSize+2)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = id.AppendJSON(buf[:0])
+	}
+	runtime.KeepAlive(buf)
+}
+
+func TestAppendTo(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	n, data := id.AppendTo([]byte("id="))
+	if n != EncodedSize {
+		t.Fatalf("n=%d", n)
+	}
+	want := []byte("id=01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data=%s", data)
+	}
+}
+
+func TestBinaryMany(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		ids := make([]ULID, 10000)
+		for i := range ids {
+			ids[i] = Make()
+		}
+		data := AppendBinaryMany(nil, ids)
+		if len(data) != len(ids)*BinarySize {
+			t.Fatalf("len=%d", len(data))
+		}
+		got, err := ParseBinaryMany(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, ids) {
+			t.Fatal("round trip failed")
+		}
+	})
+
+	t.Run("append", func(t *testing.T) {
+		id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		data := AppendBinaryMany([]byte{0xff}, []ULID{id})
+		want := []byte{0xff, 0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if !bytes.Equal(data, want) {
+			t.Fatalf("data=%x", data)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := ParseBinaryMany(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("got %v", got)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := ParseBinaryMany(make([]byte, 33))
+		if !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func BenchmarkAppendBinaryMany(b *testing.B) {
+	ids := make([]ULID, 1000)
+	for i := range ids {
+		ids[i] = Make()
+	}
+	buf := make([]byte, 0, len(ids)*BinarySize)
+	for b.Loop() {
+		buf = AppendBinaryMany(buf[:0], ids)
+	}
+	runtime.KeepAlive(buf)
+}
+
+func BenchmarkParseBinaryMany(b *testing.B) {
+	ids := make([]ULID, 1000)
+	for i := range ids {
+		ids[i] = Make()
+	}
+	data := AppendBinaryMany(nil, ids)
+	for b.Loop() {
+		got, err := ParseBinaryMany(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		runtime.KeepAlive(got)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	id1 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id2 := MinForTime(1)
+	id3 := MaxForTime(2)
+	tests := []struct {
+		name string
+		ids  []ULID
+	}{
+		{"one", []ULID{id1}},
+		{"two", []ULID{id1, id2}},
+		{"three", []ULID{id1, id2, id3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Concat(tt.ids...)
+			if len(b) != len(tt.ids)*BinarySize {
+				t.Fatalf("len=%d", len(b))
+			}
+			got, err := SplitConcat(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.ids) {
+				t.Fatalf("want %v, got %v", tt.ids, got)
+			}
+		})
+	}
+
+	t.Run("invalid size", func(t *testing.T) {
+		b := Concat(id1, id2)
+		if _, err := SplitConcat(b[:len(b)-1]); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestLengthPrefixed(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("text", func(t *testing.T) {
+		data := id.AppendLengthPrefixed(nil)
+		want := append([]byte{26}, "01ARZ3NDEKTSV4RRFFQ69G5FAV"...)
+		if !bytes.Equal(data, want) {
+			t.Fatalf("data=%q", data)
+		}
+
+		got, rest, err := ParseLengthPrefixed(append(data, "rest"...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+		if string(rest) != "rest" {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data := id.AppendBinaryLengthPrefixed(nil)
+		want := []byte{16, 0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+		if !bytes.Equal(data, want) {
+			t.Fatalf("data=%x", data)
+		}
+
+		got, rest, err := ParseBinaryLengthPrefixed(append(data, "rest"...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+		if string(rest) != "rest" {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		if _, _, err := ParseLengthPrefixed(id.AppendBinaryLengthPrefixed(nil)); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, _, err := ParseBinaryLengthPrefixed(id.AppendLengthPrefixed(nil)); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		data := id.AppendLengthPrefixed(nil)
+		if _, _, err := ParseLengthPrefixed(data[:len(data)-1]); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+		data = id.AppendBinaryLengthPrefixed(nil)
+		if _, _, err := ParseBinaryLengthPrefixed(data[:len(data)-1]); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, _, err := ParseLengthPrefixed(nil); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	tests := []struct {
+		name string
+		b    string
+		err  error // error of ParseBytes
+		errN error // error of ParseBytesN
+	}{
+		{"exact", "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil, nil},
+		{"trailing bytes", "01ARZ3NDEKTSV4RRFFQ69G5FAV\r\nrest", ErrInvalidSize, nil},
+		{"trailing invalid characters", "01ARZ3NDEKTSV4RRFFQ69G5FAV!!", ErrInvalidSize, nil},
+		{"too short", "01ARZ3NDEKTSV4RRFFQ69G5FA", ErrInvalidSize, ErrInvalidSize},
+		{"empty", "", ErrEmpty, ErrEmpty},
+		{"invalid character", "01ARZ3NDEKTSV4RRFFQ69G5FAU", ErrInvalidCharacter, ErrInvalidCharacter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseBytes([]byte(tt.b))
+			if err != tt.err {
+				t.Fatalf("ParseBytes: want %v, got %v", tt.err, err)
+			}
+			if err == nil && id != want {
+				t.Fatalf("ParseBytes: want %v, got %v", want, id)
+			}
+
+			id, err = ParseBytesN([]byte(tt.b))
+			if err != tt.errN {
+				t.Fatalf("ParseBytesN: want %v, got %v", tt.errN, err)
+			}
+			if err == nil && id != want {
+				t.Fatalf("ParseBytesN: want %v, got %v", want, id)
+			}
+		})
+	}
+}
+
+func TestDecodePrefix(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+
+	t.Run("text", func(t *testing.T) {
+		got, rest, err := DecodePrefix([]byte("01ARZ3NDEKTSV4RRFFQ69G5FAV|rest"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+		if string(rest) != "|rest" {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+
+	t.Run("text without rest", func(t *testing.T) {
+		got, rest, err := DecodePrefix([]byte("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data := append(id[:], "rest"...)
+		got, rest, err := DecodeBinaryPrefix(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Fatalf("want %v, got %v", id, got)
+		}
+		if string(rest) != "rest" {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		if _, _, err := DecodePrefix([]byte("01ARZ3NDEKTSV4RRFFQ69G5FA")); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, _, err := DecodeBinaryPrefix(id[:BinarySize-1]); !errors.Is(err, ErrInvalidSize) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("invalid character", func(t *testing.T) {
+		data := []byte("01ARZ3NDEKTSV4RRFFQ69G5FAU|rest")
+		_, rest, err := DecodePrefix(data)
+		if !errors.Is(err, ErrInvalidCharacter) {
+			t.Fatalf("err=%v", err)
+		}
+		if !bytes.Equal(rest, data) {
+			t.Fatalf("rest=%q", rest)
+		}
+	})
+}
+
+func BenchmarkString(b *testing.B) {
+	id := Make()
+	b.ReportAllocs()
+	for b.Loop() {
+		runtime.KeepAlive(id.String())
+	}
+}
+
+func BenchmarkMarshalText(b *testing.B) {
+	id := Make()
+	for b.Loop() {
+		s, err := id.MarshalText()
+		if err != nil {
+			b.Fatal(err)
+		}
+		runtime.KeepAlive(s)
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector changes escape analysis")
+	}
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	buf := make([]byte, 0, 2*BinarySize)
+	var sink ULID
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Make", func() { sink = Make() }},
+		{"MakeMonotonic", func() { sink = MakeMonotonic() }},
+		{"Parse", func() { sink, _ = Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV") }},
+		{"AppendText", func() { buf, _ = id.AppendText(buf[:0]) }},
+		{"AppendBinary", func() { buf, _ = id.AppendBinary(buf[:0]) }},
+		{"MarshalBinaryTo", func() { buf = id.MarshalBinaryTo(buf[:0]) }},
+		{"AppendJSON", func() { buf = id.AppendJSON(buf[:0]) }},
+		{"AppendHex", func() { buf = id.AppendHex(buf[:0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.f); allocs != 0 {
+				t.Fatalf("allocs=%v", allocs)
+			}
+		})
+	}
+	runtime.KeepAlive(sink)
+}
+
+func TestSortKey64(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if key := id.SortKey64(); key != 0x01563e3ab5d3d676 {
+		t.Fatalf("key=%x", key)
+	}
+
+	// Test that the ordering agrees with Compare for distinct timestamps.
+	ids := GenerateSpread(1000, time.UnixMilli(0), time.UnixMilli(999))
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1].SortKey64() >= ids[i].SortKey64() {
+			t.Fatalf("ids[%d]=%v ids[%d]=%v", i-1, ids[i-1], i, ids[i])
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	r, g, b := id.Fingerprint()
+	if r != 0xd6^0x61^0x93^0x5b || g != 0x76^0xef^0x02 || b != 0x4c^0xb9^0xbd {
+		t.Fatalf("r=%02x g=%02x b=%02x", r, g, b)
+	}
+
+	// Test that the same ULID always renders the same color and different ULIDs usually differ.
+	seen := make(map[[3]uint8]int)
+	for range 1000 {
+		id := Make()
+		r1, g1, b1 := id.Fingerprint()
+		r2, g2, b2 := id.Fingerprint()
+		if r1 != r2 || g1 != g2 || b1 != b2 {
+			t.Fatalf("not deterministic: %v", id)
+		}
+		seen[[3]uint8{r1, g1, b1}]++
+	}
+	if len(seen) < 990 {
+		t.Fatalf("too many collisions: %d distinct colors", len(seen))
+	}
+}
+
+func TestKey(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	key := id.Key()
+	if key != [16]byte{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b} {
+		t.Fatalf("key=%x", key)
+	}
+
+	// Test that the key is a copy.
+	key[0] = 0xff
+	if id[0] != 0x01 {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+}
+
+func TestWipe(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id.Wipe()
+	if id != Zero {
+		t.Fatalf("id=%x", [16]byte(id))
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !Zero.IsZero() {
+		t.Fatalf("Zero.IsZero()=%v", Zero.IsZero())
+	}
+	if Make().IsZero() {
+		t.Fatalf("Make().IsZero()=%v", Make().IsZero())
+	}
+}
+
+func BenchmarkIsZero(b *testing.B) {
+	id := Make()
 	for b.Loop() {
 		runtime.KeepAlive(id.IsZero())
 	}
 }
 
+func TestIsStrictlySorted(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []ULID
+		want bool
+	}{
+		{"empty", nil, true},
+		{"one", []ULID{MinForTime(1)}, true},
+		{"sorted unique", []ULID{MinForTime(1), MaxForTime(1), MinForTime(2), MinForTime(3)}, true},
+		{"sorted with duplicates", []ULID{MinForTime(1), MinForTime(2), MinForTime(2), MinForTime(3)}, false},
+		{"unsorted", []ULID{MinForTime(1), MinForTime(3), MinForTime(2)}, false},
+		{"descending", []ULID{MinForTime(3), MinForTime(2), MinForTime(1)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStrictlySorted(tt.ids); got != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	sorted := []ULID{MinForTime(10), MinForTime(20), MaxForTime(20), MinForTime(30)}
+	tests := []struct {
+		name   string
+		sorted []ULID
+		target ULID
+		index  int
+		found  bool
+	}{
+		{"first", sorted, MinForTime(10), 0, true},
+		{"middle", sorted, MaxForTime(20), 2, true},
+		{"last", sorted, MinForTime(30), 3, true},
+		{"before first", sorted, Zero, 0, false},
+		{"absent", sorted, MinForTime(25), 3, false},
+		{"after last", sorted, MaxForTime(30), 4, false},
+		{"empty", nil, MinForTime(10), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, found := BinarySearch(tt.sorted, tt.target)
+			if index != tt.index || found != tt.found {
+				t.Fatalf("want (%d, %v), got (%d, %v)", tt.index, tt.found, index, found)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	id1 := MinForTime(1)
+	id2 := MinForTime(2)
+	id3 := MinForTime(3)
+	tests := []struct {
+		name string
+		ids  []ULID
+		want []ULID
+	}{
+		{"empty", nil, nil},
+		{"no duplicates", []ULID{id3, id1, id2}, nil},
+		{"some duplicates", []ULID{id3, id1, id2, id3, id1, id3}, []ULID{id1, id3}},
+		{"all identical", []ULID{id2, id2, id2, id2}, []ULID{id2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.ids)
+			got := FindDuplicates(tt.ids)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+			if !slices.Equal(tt.ids, orig) {
+				t.Fatalf("ids was modified: %v", tt.ids)
+			}
+		})
+	}
+}
+
+func TestIsSuspicious(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ULID
+		want bool
+	}{
+		{"zero", Zero, true},
+		{"max", ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, true},
+		{"max entropy", MaxForTime(0x1563e3ab5d3), true},
+		{"all same bytes", ULID{0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42}, true},
+		{"normal", ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}, false},
+		{"zero entropy", MinForTime(0x1563e3ab5d3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSuspicious(tt.id); got != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"canonical", "01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"zero", "00000000000000000000000000", true},
+		{"max", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", true},
+		{"lower case", "01arz3ndektsv4rrffq69g5fav", false},
+		{"mixed case", "01ARZ3NDEKTSV4RRFFQ69G5FAv", false},
+		{"lower case max", "7zzzzzzzzzzzzzzzzzzzzzzzzz", false},
+		{"mixed case leading", "01aRZ3NDEKTSV4RRFFQ69G5FAV", false},
+		{"mixed case every other", "0000xSnJg0mQjHbF4Qx1eFd6y3", false},
+		{"overflow", "80000000000000000000000000", false},
+		{"invalid character", "01ARZ3NDEKTSV4RRFFQ69G5FAU", false},
+		{"too short", "01ARZ3NDEKTSV4RRFFQ69G5FA", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCanonical(tt.s); got != tt.want {
+				t.Fatalf("IsCanonical(%q)=%v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlphabet(t *testing.T) {
+	alphabet := Alphabet()
+	if alphabet != enc {
+		t.Fatalf("alphabet=%s", alphabet)
+	}
+	if len(alphabet) != 32 {
+		t.Fatalf("len=%d", len(alphabet))
+	}
+}
+
+func TestDecodeTable(t *testing.T) {
+	table := DecodeTable()
+	if table != dec {
+		t.Fatal("the table does not match dec")
+	}
+	for i := range len(enc) {
+		if table[enc[i]] != int8(i) {
+			t.Fatalf("table[%q]=%d, want %d", enc[i], table[enc[i]], i)
+		}
+	}
+
+	// Test that the returned table is a copy.
+	table['0'] = -1
+	if dec['0'] != 0 {
+		t.Fatal("dec was modified")
+	}
+}
+
+func TestCommonPrefixLen(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"identical", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAV", 26},
+		{"disjoint", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", 0},
+		{"same time", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEK0000000000000000", 10},
+		{"partial", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW", 25},
+		{"different lengths", "01ARZ3ND", "01ARZ3NDEKTSV4RRFFQ69G5FAV", 8},
+		{"empty", "", "01ARZ3NDEKTSV4RRFFQ69G5FAV", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommonPrefixLen(tt.a, tt.b); got != tt.want {
+				t.Fatalf("want %d, got %d", tt.want, got)
+			}
+			if got := CommonPrefixLen(tt.b, tt.a); got != tt.want {
+				t.Fatalf("not symmetric: want %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+		err  error
+	}{
+		{"canonical", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil},
+		{"lower case", "01arz3ndektsv4rrffq69g5fav", "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil},
+		{"aliases", "O1ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil},
+		{"lower case aliases", "oiARZ3NDEKTSV4RRFFQ69G5FAl", "01ARZ3NDEKTSV4RRFFQ69G5FA1", nil},
+		{"overflow", "80000000000000000000000000", "80000000000000000000000000", nil},
+		{"invalid character", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "", ErrInvalidCharacter},
+		{"too short", "01ARZ3NDEKTSV4RRFFQ69G5FA", "", ErrInvalidSize},
+		{"empty", "", "", ErrEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeString(tt.s)
+			if err != tt.err {
+				t.Fatalf("want %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func BenchmarkNormalizeString(b *testing.B) {
+	const s = "0000xsnjg0mqjhbf4qx1efd6y3"
+
+	b.Run("NormalizeString", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			got, err := NormalizeString(s)
+			if err != nil {
+				b.Fatal(err)
+			}
+			runtime.KeepAlive(got)
+		}
+	})
+
+	b.Run("ParseAndString", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			id, err := Parse(s)
+			if err != nil {
+				b.Fatal(err)
+			}
+			runtime.KeepAlive(id.String())
+		}
+	})
+}
+
 func TestCompare(t *testing.T) {
 	id1, err := Parse("0000XSNJG0MQJHBF4QX1EFD6Y3")
 	if err != nil {
@@ -428,6 +2992,191 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestEntropyHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b ULID
+		want int
+	}{
+		{Zero, Zero, 0},
+		{MinForTime(0), MaxForTime(0xFFFFFFFFFFFF), 80},
+		{MinForTime(1), MinForTime(2), 0},
+		{
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80},
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x00},
+			6,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.a.EntropyHammingDistance(tt.b); got != tt.want {
+			t.Errorf("%v.EntropyHammingDistance(%v)=%d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestXORDistance(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	max := ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	tests := []struct {
+		name string
+		a, b ULID
+		want ULID
+	}{
+		{"self", id, id, Zero},
+		{"zero", id, Zero, id},
+		{"max", id, max, ULID{0xfe, 0xa9, 0xc1, 0xc5, 0x4a, 0x2c, 0x29, 0x89, 0xb3, 0x9e, 0x10, 0x46, 0x6c, 0xfd, 0x42, 0xa4}},
+		{
+			"last bit",
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+			ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5a},
+			ULID{15: 0x01},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.XORDistance(tt.b); got != tt.want {
+				t.Fatalf("want %x, got %x", [16]byte(tt.want), [16]byte(got))
+			}
+			if got := tt.b.XORDistance(tt.a); got != tt.want {
+				t.Fatalf("not symmetric: want %x, got %x", [16]byte(tt.want), [16]byte(got))
+			}
+		})
+	}
+}
+
+func TestCompareNullLast(t *testing.T) {
+	id1, err := Parse("0000XSNJG0MQJHBF4QX1EFD6Y3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		a, b ULID
+		want int
+	}{
+		{id1, id2, -1},
+		{id2, id1, 1},
+		{id1, id1, 0},
+		{Zero, id1, 1},
+		{id1, Zero, -1},
+		{Zero, Zero, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.CompareNullLast(tt.b); got != tt.want {
+			t.Errorf("%v.CompareNullLast(%v)=%d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSameEntropy(t *testing.T) {
+	id1 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id2 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd4, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	id3 := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5c}
+	if !id1.SameEntropy(id2) {
+		t.Fatalf("id1.SameEntropy(id2)=%v", id1.SameEntropy(id2))
+	}
+	if id1.SameEntropy(id3) {
+		t.Fatalf("id1.SameEntropy(id3)=%v", id1.SameEntropy(id3))
+	}
+}
+
+func TestShard(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	// the low 64 bits are 0x4c61efb99302bd5b = 5503943799937875291.
+	tests := []struct {
+		n     int
+		shard int
+		key   string
+	}{
+		{1, 0, "0"},
+		{7, 6, "6"},
+		{10, 1, "1"},
+		{11, 10, "10"},
+		{1000, 291, "291"},
+		{1024, 347, "0347"},
+	}
+	for _, tt := range tests {
+		if got := id.Shard(tt.n); got != tt.shard {
+			t.Errorf("Shard(%d)=%d, want %d", tt.n, got, tt.shard)
+		}
+		if got := id.ShardKey(tt.n); got != tt.key {
+			t.Errorf("ShardKey(%d)=%q, want %q", tt.n, got, tt.key)
+		}
+	}
+
+	// all keys for the same n have the same width.
+	for range 1000 {
+		id := Make()
+		shard := id.Shard(100)
+		if shard < 0 || shard >= 100 {
+			t.Fatalf("shard=%d", shard)
+		}
+		if key := id.ShardKey(100); len(key) != 2 {
+			t.Fatalf("key=%q", key)
+		}
+	}
+}
+
+func TestTag(t *testing.T) {
+	id := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if tag := id.Tag(); tag != 0xd6 {
+		t.Fatalf("tag=%#x", tag)
+	}
+
+	tagged := id.WithTag(0x2a)
+	want := ULID{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0x2a, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b}
+	if tagged != want {
+		t.Fatalf("want %v, got %v", want, tagged)
+	}
+	if tag := tagged.Tag(); tag != 0x2a {
+		t.Fatalf("tag=%#x", tag)
+	}
+	if tagged.Time() != id.Time() {
+		t.Fatalf("time changed: %d", tagged.Time())
+	}
+
+	// WithTag must not modify the receiver.
+	if id[6] != 0xd6 {
+		t.Fatalf("id was modified: %v", id)
+	}
+}
+
+func TestOrderingStability(t *testing.T) {
+	ids := []ULID{
+		Zero,
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5b},
+		{0x01, 0x56, 0x3e, 0x3a, 0xb5, 0xd3, 0xd6, 0x76, 0x4c, 0x61, 0xef, 0xb9, 0x93, 0x02, 0xbd, 0x5c},
+	}
+	for range 1000 {
+		var id ULID
+		rand.Read(id[:])
+		ids = append(ids, id)
+	}
+
+	byCompare := slices.Clone(ids)
+	slices.SortFunc(byCompare, func(a, b ULID) int {
+		return a.Compare(b)
+	})
+	byBinary := slices.Clone(ids)
+	slices.SortFunc(byBinary, func(a, b ULID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	byText := slices.Clone(ids)
+	slices.SortFunc(byText, func(a, b ULID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	if !slices.Equal(byCompare, byBinary) {
+		t.Fatal("the order by Compare and the order by the binary encoding are different")
+	}
+	if !slices.Equal(byCompare, byText) {
+		t.Fatal("the order by Compare and the order by the text encoding are different")
+	}
+}
+
 func BenchmarkCompare(b *testing.B) {
 	id := Make()
 	for b.Loop() {